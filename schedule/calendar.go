@@ -0,0 +1,337 @@
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// A Calendar represents a set of excluded times, such as public holidays or
+// maintenance windows. See Trigger.Exclude.
+type Calendar interface {
+	// Excludes returns whether tm is excluded by the calendar.
+	Excludes(tm time.Time) bool
+}
+
+// calendarDate represents a date, independent of location.
+type calendarDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// A HolidayCalendar represents a Calendar of excluded dates, time ranges
+// and weekdays.
+// Excluded dates are matched against the date of a time in its own
+// location, while excluded ranges are absolute.
+type HolidayCalendar struct {
+	dates     map[calendarDate]bool
+	ranges    [][2]time.Time
+	weekdays  [7]bool
+	recurring []recurringEvent
+}
+
+// recurringEvent represents an event of an iCalendar file repeating
+// following its RRULE. All-day events span a number of days, while other
+// events last for a fixed duration.
+type recurringEvent struct {
+	rule   *rrule
+	days   int
+	length time.Duration
+}
+
+// icsEvent holds the content lines of an iCalendar event describing when it
+// takes place.
+type icsEvent struct {
+	start, end, duration, rule string
+	exdates                    []string
+}
+
+// NewHolidayCalendar creates a new HolidayCalendar without any exclusions.
+func NewHolidayCalendar() *HolidayCalendar {
+	return &HolidayCalendar{
+		dates: make(map[calendarDate]bool),
+	}
+}
+
+// LoadICS creates a new HolidayCalendar excluding the events of an iCalendar
+// (.ics) file. All-day events exclude the dates they span, while events with
+// a start and end time exclude the time range between them. The end of an
+// event is given by either its DTEND or DURATION.
+// Recurring events exclude every occurrence of their RRULE, except for their
+// EXDATE values. See Trigger.RRule for the supported rule parts.
+// If an event cannot be parsed or uses RDATE or EXRULE, which are not
+// supported, an error is returned.
+func LoadICS(r io.Reader) (*HolidayCalendar, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+	c := NewHolidayCalendar()
+	var event icsEvent
+	inEvent := false
+	for _, line := range lines {
+		name, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			name, value = line[:i], line[i+1:]
+		}
+		property := strings.ToUpper(strings.SplitN(name, ";", 2)[0])
+		if !inEvent {
+			if property == "BEGIN" && strings.EqualFold(value, "VEVENT") {
+				inEvent, event = true, icsEvent{}
+			}
+			continue
+		}
+		switch property {
+		case "END":
+			if strings.EqualFold(value, "VEVENT") {
+				if err := c.excludeEvent(event); err != nil {
+					return nil, err
+				}
+				inEvent = false
+			}
+		case "DTSTART":
+			event.start = line
+		case "DTEND":
+			event.end = line
+		case "DURATION":
+			event.duration = line
+		case "RRULE":
+			if event.rule != "" {
+				return nil, fmt.Errorf("schedule: event with more than one RRULE")
+			}
+			event.rule = line
+		case "EXDATE":
+			event.exdates = append(event.exdates, line)
+		case "RDATE", "EXRULE":
+			return nil, fmt.Errorf("schedule: unsupported event property %s", property)
+		}
+	}
+	return c, nil
+}
+
+// unfoldICS returns the unfolded content lines of an iCalendar file.
+// If the file could not be read, an error is returned.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("schedule: could not read iCalendar file: %v", err)
+	}
+	return lines, nil
+}
+
+// excludeEvent excludes every occurrence of an event.
+func (c *HolidayCalendar) excludeEvent(event icsEvent) error {
+	if event.start == "" {
+		return fmt.Errorf("schedule: event without DTSTART")
+	}
+	i := strings.Index(event.start, ":")
+	if i < 0 {
+		return fmt.Errorf("schedule: event DTSTART without a value: %s", event.start)
+	}
+	from, err := parseICalTime(event.start[:i], event.start[i+1:])
+	if err != nil {
+		return err
+	}
+	allDay := len(event.start)-i-1 == 8
+	to := from
+	if allDay {
+		to = from.AddDate(0, 0, 1)
+	}
+	switch {
+	case event.end != "" && event.duration != "":
+		return fmt.Errorf("schedule: event with both DTEND and DURATION")
+	case event.end != "":
+		i = strings.Index(event.end, ":")
+		if i < 0 {
+			return fmt.Errorf("schedule: event DTEND without a value: %s", event.end)
+		}
+		if to, err = parseICalTime(event.end[:i], event.end[i+1:]); err != nil {
+			return err
+		}
+	case event.duration != "":
+		i = strings.Index(event.duration, ":")
+		if i < 0 {
+			return fmt.Errorf("schedule: event DURATION without a value: %s", event.duration)
+		}
+		days, d, err := parseICalDuration(event.duration[i+1:])
+		if err != nil {
+			return err
+		}
+		to = from.AddDate(0, 0, days).Add(d)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("schedule: event ends before it starts: %s", event.start)
+	}
+	if event.rule != "" {
+		rule, err := parseRRule(strings.Join(append([]string{event.start, event.rule}, event.exdates...), "\n"))
+		if err != nil {
+			return err
+		}
+		e := recurringEvent{rule: rule, length: to.Sub(from)}
+		if allDay {
+			e.days = civilDays(from, to)
+		}
+		c.recurring = append(c.recurring, e)
+		return nil
+	}
+	if !allDay {
+		c.ExcludeRange(from, to)
+		return nil
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		c.ExcludeDate(day)
+	}
+	return nil
+}
+
+// parseICalDuration parses an iCalendar DURATION value, e.g. "P1D" or
+// "PT1H30M", into a number of days and the remaining exact duration.
+func parseICalDuration(value string) (int, time.Duration, error) {
+	fail := fmt.Errorf("schedule: invalid duration %q", value)
+	if !strings.HasPrefix(value, "P") || strings.HasSuffix(value, "T") || len(value) < 3 {
+		return 0, 0, fail
+	}
+	days, d := 0, time.Duration(0)
+	inTime := false
+	n := -1
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			if n < 0 {
+				n = 0
+			}
+			n = n*10 + int(r-'0')
+			continue
+		case r == 'T' && !inTime && n < 0:
+			inTime = true
+			continue
+		case n < 0:
+			return 0, 0, fail
+		case r == 'W' && !inTime:
+			days += 7 * n
+		case r == 'D' && !inTime:
+			days += n
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, 0, fail
+		}
+		n = -1
+	}
+	if n >= 0 {
+		return 0, 0, fail
+	}
+	return days, d, nil
+}
+
+// ExcludeDate excludes the whole date of tm.
+func (c *HolidayCalendar) ExcludeDate(tm time.Time) *HolidayCalendar {
+	y, m, d := tm.Date()
+	c.dates[calendarDate{y, m, d}] = true
+	return c
+}
+
+// ExcludeRange excludes the times from, and including, from up to, but not
+// including, to.
+func (c *HolidayCalendar) ExcludeRange(from, to time.Time) *HolidayCalendar {
+	c.ranges = append(c.ranges, [2]time.Time{from, to})
+	return c
+}
+
+// ExcludeWeekday excludes every occurrence of the given weekdays.
+func (c *HolidayCalendar) ExcludeWeekday(days ...time.Weekday) *HolidayCalendar {
+	for _, d := range days {
+		c.weekdays[d] = true
+	}
+	return c
+}
+
+// Excludes returns whether tm is excluded by the calendar.
+func (c *HolidayCalendar) Excludes(tm time.Time) bool {
+	y, m, d := tm.Date()
+	if c.weekdays[tm.Weekday()] || c.dates[calendarDate{y, m, d}] {
+		return true
+	}
+	for _, r := range c.ranges {
+		if !tm.Before(r[0]) && tm.Before(r[1]) {
+			return true
+		}
+	}
+	for _, e := range c.recurring {
+		if e.excludes(tm) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludes returns whether tm is within an occurrence of the event. Like
+// excluded dates, all-day occurrences are matched against the date of tm in
+// its own location.
+func (e recurringEvent) excludes(tm time.Time) bool {
+	start := e.rule.dtstart
+	if e.days > 0 {
+		y, m, d := tm.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, start.Location())
+		n := e.rule.next(start, day.AddDate(0, 0, -e.days))
+		return !n.IsZero() && !n.After(day)
+	}
+	// The first occurrence ending after tm is the only one which may
+	// contain it.
+	n := e.rule.next(start, tm.Add(-e.length))
+	return !n.IsZero() && !n.After(tm)
+}
+
+// calendarUnion is a Calendar excluding the times excluded by any of its
+// calendars.
+type calendarUnion []Calendar
+
+func (u calendarUnion) Excludes(tm time.Time) bool {
+	for _, c := range u {
+		if c.Excludes(tm) {
+			return true
+		}
+	}
+	return false
+}
+
+// calendarIntersection is a Calendar excluding the times excluded by all of
+// its calendars.
+type calendarIntersection []Calendar
+
+func (i calendarIntersection) Excludes(tm time.Time) bool {
+	for _, c := range i {
+		if !c.Excludes(tm) {
+			return false
+		}
+	}
+	return len(i) > 0
+}
+
+// Union creates a Calendar excluding the times excluded by any of the given
+// calendars.
+func Union(cals ...Calendar) Calendar {
+	return calendarUnion(cals)
+}
+
+// Intersection creates a Calendar excluding only the times excluded by all
+// of the given calendars.
+func Intersection(cals ...Calendar) Calendar {
+	return calendarIntersection(cals)
+}
@@ -0,0 +1,281 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// calendarUnit represents the period of a calendarRule.
+type calendarUnit int
+
+const (
+	unitDay calendarUnit = iota
+	unitWeek
+	unitMonth
+)
+
+// maxCalendarDays is the number of days, per period, searched for the next
+// matching date of a calendarRule before giving up.
+const maxCalendarDays = 3000
+
+// calendarRule is a recurrence firing on calendar dates, every n days, weeks
+// or months counted from the start date of the Trigger.
+type calendarRule struct {
+	unit     calendarUnit
+	n        int
+	weekdays []time.Weekday
+	monthday int
+	nth      int
+	nthDay   time.Weekday
+	clock    bool
+	hour     int
+	minute   int
+}
+
+// Each starts a calendar schedule for the Trigger, repeating every n periods.
+// The period defaults to days and is set by Day, Week or Month.
+// E.g. Trigger.Each(2).Weeks().On(time.Friday).AtClock("17:00").
+// Each is the calendar counterpart of Every, which already takes a duration
+// string for fixed intervals, so a daily schedule reads
+// Each(1).Day().AtClock("09:00") rather than Every(1).Day().At("09:00").
+// If 0 or a negative value is provided, n will be set to 1.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) Each(n int) *Trigger {
+	if n < 1 {
+		n = 1
+	}
+	t.interval = 0
+	t.rule = &calendarRule{unit: unitDay, n: n}
+	return t
+}
+
+// Day sets the period of the calendar schedule to days.
+func (t *Trigger) Day() *Trigger {
+	t.calendar().unit = unitDay
+	return t
+}
+
+// Days is an alias for Day.
+func (t *Trigger) Days() *Trigger {
+	return t.Day()
+}
+
+// Week sets the period of the calendar schedule to weeks, starting on
+// Monday. Unless set by On, the job runs on the weekday of the start time.
+func (t *Trigger) Week() *Trigger {
+	t.calendar().unit = unitWeek
+	return t
+}
+
+// Weeks is an alias for Week.
+func (t *Trigger) Weeks() *Trigger {
+	return t.Week()
+}
+
+// Month sets the period of the calendar schedule to months.
+// Unless set by MonthlyOn, LastDayOfMonth, NthWeekday or On, the job runs on
+// the day of the month of the start time.
+func (t *Trigger) Month() *Trigger {
+	t.calendar().unit = unitMonth
+	return t
+}
+
+// Months is an alias for Month.
+func (t *Trigger) Months() *Trigger {
+	return t.Month()
+}
+
+// On restricts the calendar schedule to the given weekdays.
+func (t *Trigger) On(days ...time.Weekday) *Trigger {
+	c := t.calendar()
+	c.weekdays = append(c.weekdays, days...)
+	return t
+}
+
+// Monday sets a weekly calendar schedule running on Mondays.
+func (t *Trigger) Monday() *Trigger {
+	return t.Week().On(time.Monday)
+}
+
+// Tuesday sets a weekly calendar schedule running on Tuesdays.
+func (t *Trigger) Tuesday() *Trigger {
+	return t.Week().On(time.Tuesday)
+}
+
+// Wednesday sets a weekly calendar schedule running on Wednesdays.
+func (t *Trigger) Wednesday() *Trigger {
+	return t.Week().On(time.Wednesday)
+}
+
+// Thursday sets a weekly calendar schedule running on Thursdays.
+func (t *Trigger) Thursday() *Trigger {
+	return t.Week().On(time.Thursday)
+}
+
+// Friday sets a weekly calendar schedule running on Fridays.
+func (t *Trigger) Friday() *Trigger {
+	return t.Week().On(time.Friday)
+}
+
+// Saturday sets a weekly calendar schedule running on Saturdays.
+func (t *Trigger) Saturday() *Trigger {
+	return t.Week().On(time.Saturday)
+}
+
+// Sunday sets a weekly calendar schedule running on Sundays.
+func (t *Trigger) Sunday() *Trigger {
+	return t.Week().On(time.Sunday)
+}
+
+// AtClock sets the time of day of the calendar schedule. The value passed
+// needs to be in 24-hour "15:04" format, in the location of the start time.
+// If the provided string cannot be parsed, the function will panic with
+// the error.
+// Unless set, the job runs at the time of day of the start time.
+// AtClock is named apart from At, which schedules a single run at a given
+// time.
+func (t *Trigger) AtClock(str string) *Trigger {
+	tm, err := time.Parse("15:04", str)
+	if err != nil {
+		panic(err)
+	}
+	c := t.calendar()
+	c.clock = true
+	c.hour, c.minute = tm.Hour(), tm.Minute()
+	return t
+}
+
+// MonthlyOn sets a monthly calendar schedule running on the given day of the
+// month. Months without the given day are skipped.
+// If the day is not between 1 and 31, the function will panic with an error.
+// See LastDayOfMonth.
+func (t *Trigger) MonthlyOn(day int) *Trigger {
+	if day < 1 || day > 31 {
+		panic(fmt.Errorf("schedule: invalid day of the month %d", day))
+	}
+	return t.monthlyOn(day)
+}
+
+// LastDayOfMonth sets a monthly calendar schedule running on the last day of
+// the month.
+func (t *Trigger) LastDayOfMonth() *Trigger {
+	return t.monthlyOn(-1)
+}
+
+// monthlyOn sets a monthly calendar schedule running on the given day of the
+// month, where -1 is the last day.
+func (t *Trigger) monthlyOn(day int) *Trigger {
+	c := t.Month().calendar()
+	c.monthday = day
+	c.nth = 0
+	return t
+}
+
+// NthWeekday sets a monthly calendar schedule running on the nth given
+// weekday of the month. If n is negative, it is counted from the end of the
+// month, e.g. NthWeekday(-1, time.Friday) runs on the last Friday.
+// If n is 0 or not between -5 and 5, the function will panic with an error.
+func (t *Trigger) NthWeekday(n int, day time.Weekday) *Trigger {
+	if n == 0 || n < -5 || n > 5 {
+		panic(fmt.Errorf("schedule: invalid weekday ordinal %d", n))
+	}
+	c := t.Month().calendar()
+	c.nth = n
+	c.nthDay = day
+	return t
+}
+
+// calendar returns the calendar schedule of the Trigger, starting a daily
+// one if none is set.
+func (t *Trigger) calendar() *calendarRule {
+	if c, ok := t.rule.(*calendarRule); ok {
+		return c
+	}
+	t.Each(1)
+	return t.rule.(*calendarRule)
+}
+
+func (c *calendarRule) next(start, after time.Time) time.Time {
+	loc := start.Location()
+	from := after.In(loc)
+	if start.After(after) {
+		from = start
+	}
+	hour, minute, second := start.Clock()
+	if c.clock {
+		hour, minute, second = c.hour, c.minute, 0
+	}
+	for i := 0; i < maxCalendarDays*c.n; i++ {
+		next := time.Date(from.Year(), from.Month(), from.Day()+i, hour, minute, second, 0, loc)
+		if c.matches(start, next) && next.After(after) && !next.Before(start) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// matches returns whether the date of tm matches the rule.
+func (c *calendarRule) matches(start, tm time.Time) bool {
+	switch c.unit {
+	case unitDay:
+		return civilDays(start, tm)%c.n == 0 && c.onWeekday(tm, true)
+	case unitWeek:
+		weeks := (civilDays(start, tm) + weekdayOffset(start)) / 7
+		return weeks%c.n == 0 && c.onWeekday(tm, tm.Weekday() == start.Weekday())
+	case unitMonth:
+		months := (tm.Year()-start.Year())*12 + int(tm.Month()-start.Month())
+		return months%c.n == 0 && c.onWeekday(tm, true) && c.onMonthday(start, tm)
+	}
+	return false
+}
+
+// onWeekday returns whether tm falls on one of the weekdays of the rule.
+// If the rule has no weekdays, def is returned.
+func (c *calendarRule) onWeekday(tm time.Time, def bool) bool {
+	if len(c.weekdays) == 0 {
+		return def
+	}
+	for _, d := range c.weekdays {
+		if tm.Weekday() == d {
+			return true
+		}
+	}
+	return false
+}
+
+// onMonthday returns whether tm falls on the day of the month of the rule.
+func (c *calendarRule) onMonthday(start, tm time.Time) bool {
+	days := daysIn(tm.Year(), tm.Month())
+	switch {
+	case c.nth > 0:
+		return tm.Weekday() == c.nthDay && (tm.Day()-1)/7 == c.nth-1
+	case c.nth < 0:
+		return tm.Weekday() == c.nthDay && (days-tm.Day())/7 == -c.nth-1
+	case c.monthday < 0:
+		return tm.Day() == days
+	case c.monthday > 0:
+		return tm.Day() == c.monthday
+	case len(c.weekdays) > 0:
+		return true
+	}
+	return tm.Day() == start.Day()
+}
+
+// civilDays returns the number of calendar days from the date of a to the
+// date of b, ignoring any daylight saving time changes.
+func civilDays(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// daysIn returns the number of days in the given month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// weekdayOffset returns the number of days since the Monday starting the
+// week of tm.
+func weekdayOffset(tm time.Time) int {
+	return (int(tm.Weekday()) + 6) % 7
+}
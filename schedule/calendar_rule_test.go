@@ -0,0 +1,162 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrigger_Each(t *testing.T) {
+	trigger := NewTrigger().Each(0)
+	c, ok := trigger.rule.(*calendarRule)
+	if !ok {
+		t.Fatal("Each did not set a calendar schedule")
+	}
+	if c.n != 1 || c.unit != unitDay {
+		t.Errorf("Calendar schedule did not match. Got %+v", c)
+	}
+}
+
+func TestTrigger_AtClockPanic(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("AtClock did not panic with an invalid clock string")
+		}
+	}()
+	NewTrigger().Each(1).Day().AtClock("25:00")
+}
+
+func TestTrigger_MonthlyOnPanic(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func()
+	}{
+		{"MonthlyOn(0)", func() { NewTrigger().MonthlyOn(0) }},
+		{"MonthlyOn(32)", func() { NewTrigger().MonthlyOn(32) }},
+		{"MonthlyOn(-1)", func() { NewTrigger().MonthlyOn(-1) }},
+		{"NthWeekday(0)", func() { NewTrigger().NthWeekday(0, time.Monday) }},
+		{"NthWeekday(6)", func() { NewTrigger().NthWeekday(6, time.Monday) }},
+	} {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Errorf("%s did not panic", test.name)
+				} else if _, ok := err.(error); !ok {
+					t.Errorf("%s did not panic with an error. Got %v", test.name, err)
+				}
+			}()
+			test.f()
+		}()
+	}
+}
+
+func TestCalendarRule_Next(t *testing.T) {
+	// 2028-02-09 is a Wednesday in a leap year.
+	start := time.Date(2028, 2, 9, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		trigger *Trigger
+		after   time.Time
+		next    time.Time
+	}{
+		{
+			"daily",
+			NewTrigger().From(start).Each(1).Day().AtClock("09:00"),
+			start,
+			time.Date(2028, 2, 10, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			"every 3 days",
+			NewTrigger().From(start).Each(3).Days(),
+			start,
+			time.Date(2028, 2, 12, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"weekdays",
+			NewTrigger().From(start).Each(1).Day().On(time.Monday, time.Friday).AtClock("09:00"),
+			start,
+			time.Date(2028, 2, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			"monday",
+			NewTrigger().From(start).Each(1).Monday().AtClock("08:30"),
+			start,
+			time.Date(2028, 2, 14, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			"every other friday",
+			NewTrigger().From(start).Each(2).Weeks().On(time.Friday),
+			time.Date(2028, 2, 11, 12, 0, 0, 0, time.UTC),
+			time.Date(2028, 2, 25, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"weekly on start weekday",
+			NewTrigger().From(start).Each(1).Week(),
+			start,
+			time.Date(2028, 2, 16, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"monthly on 15th",
+			NewTrigger().From(start).MonthlyOn(15).AtClock("00:00"),
+			start,
+			time.Date(2028, 2, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"monthly on 31st",
+			NewTrigger().From(start).MonthlyOn(31),
+			start,
+			time.Date(2028, 3, 31, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"monthly on 30th every other month",
+			NewTrigger().From(start).Each(2).Months().MonthlyOn(30),
+			start,
+			time.Date(2028, 4, 30, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"last day of february",
+			NewTrigger().From(start).LastDayOfMonth(),
+			start,
+			time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"last day of april",
+			NewTrigger().From(start).LastDayOfMonth(),
+			time.Date(2028, 3, 31, 12, 0, 0, 0, time.UTC),
+			time.Date(2028, 4, 30, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"second tuesday",
+			NewTrigger().From(start).NthWeekday(2, time.Tuesday),
+			start,
+			time.Date(2028, 3, 14, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			"last friday",
+			NewTrigger().From(start).NthWeekday(-1, time.Friday),
+			start,
+			time.Date(2028, 2, 25, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		if n := test.trigger.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time for %s did not match. Got %v, expected %v", test.name, n, test.next)
+		}
+	}
+}
+
+func TestCalendarRule_NextDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skipf("Could not load time zone: %v", err)
+	}
+	// Daylight saving time starts on 2028-03-26 in Europe/Oslo.
+	start := time.Date(2028, 3, 25, 9, 0, 0, 0, loc)
+	trigger := NewTrigger().From(start).Each(1).Day().AtClock("09:00")
+	next := time.Date(2028, 3, 26, 9, 0, 0, 0, loc)
+	if n := trigger.NextAfter(start); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+	if d := next.Sub(start); d != 23*time.Hour {
+		t.Errorf("Duration between runs did not match. Got %v, expected 23h", d)
+	}
+}
@@ -0,0 +1,230 @@
+package schedule
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestHolidayCalendar_ExcludeDate(t *testing.T) {
+	c := NewHolidayCalendar().ExcludeDate(time.Date(2028, 12, 25, 0, 0, 0, 0, time.UTC))
+	if !c.Excludes(time.Date(2028, 12, 25, 23, 59, 0, 0, time.UTC)) {
+		t.Error("Excluded date was not excluded")
+	}
+	if c.Excludes(time.Date(2028, 12, 26, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Date after excluded date was excluded")
+	}
+}
+
+func TestHolidayCalendar_ExcludeRange(t *testing.T) {
+	from := time.Date(2028, 1, 1, 22, 0, 0, 0, time.UTC)
+	c := NewHolidayCalendar().ExcludeRange(from, from.Add(4*time.Hour))
+	if !c.Excludes(from) || !c.Excludes(from.Add(3*time.Hour)) {
+		t.Error("Time within excluded range was not excluded")
+	}
+	if c.Excludes(from.Add(4 * time.Hour)) {
+		t.Error("End of excluded range was excluded")
+	}
+}
+
+func TestHolidayCalendar_ExcludeWeekday(t *testing.T) {
+	c := NewHolidayCalendar().ExcludeWeekday(time.Saturday, time.Sunday)
+	// 2028-01-01 is a Saturday.
+	sat := time.Date(2028, 1, 1, 12, 0, 0, 0, time.UTC)
+	if !c.Excludes(sat) || !c.Excludes(sat.AddDate(0, 0, 1)) {
+		t.Error("Excluded weekday was not excluded")
+	}
+	if c.Excludes(sat.AddDate(0, 0, 2)) {
+		t.Error("Monday was excluded")
+	}
+}
+
+func TestLoadICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Christmas",
+		"DTSTART;VALUE=DATE:20281225",
+		"DTEND;VALUE=DATE:20281227",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Maintenance",
+		"DTSTART:20280110T020000Z",
+		"DTEND:20280110T",
+		" 040000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	c, err := LoadICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		tm       time.Time
+		excluded bool
+	}{
+		{time.Date(2028, 12, 24, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2028, 12, 25, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2028, 12, 26, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2028, 12, 27, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2028, 1, 10, 3, 0, 0, 0, time.UTC), true},
+		{time.Date(2028, 1, 10, 4, 0, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		if c.Excludes(test.tm) != test.excluded {
+			t.Errorf("Exclusion of %v did not match. Expected %v", test.tm, test.excluded)
+		}
+	}
+}
+
+func TestLoadICSRecurring(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Christmas",
+		"DTSTART;VALUE=DATE:20241225",
+		"RRULE:FREQ=YEARLY",
+		"EXDATE;VALUE=DATE:20261225",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Stocktake",
+		"DTSTART;VALUE=DATE:20240630",
+		"DURATION:P2D",
+		"RRULE:FREQ=YEARLY;COUNT=3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Maintenance",
+		"DTSTART:20240101T020000Z",
+		"DURATION:PT1H30M",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	c, err := LoadICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		tm       time.Time
+		excluded bool
+	}{
+		{time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 12, 25, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2040, 12, 25, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, 12, 24, 23, 59, 0, 0, time.UTC), false},
+		{time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2027, 6, 30, 12, 0, 0, 0, time.UTC), false},
+		// 2029-01-01 is a Monday.
+		{time.Date(2029, 1, 1, 2, 0, 0, 0, time.UTC), true},
+		{time.Date(2029, 1, 1, 3, 29, 0, 0, time.UTC), true},
+		{time.Date(2029, 1, 1, 3, 30, 0, 0, time.UTC), false},
+		{time.Date(2029, 1, 2, 2, 30, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		if c.Excludes(test.tm) != test.excluded {
+			t.Errorf("Exclusion of %v did not match. Expected %v", test.tm, test.excluded)
+		}
+	}
+}
+
+func TestLoadICSDuration(t *testing.T) {
+	ics := "BEGIN:VEVENT\nDTSTART:20280110T020000Z\nDURATION:PT2H\nEND:VEVENT\n"
+	c, err := LoadICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2028, 1, 10, 2, 0, 0, 0, time.UTC)
+	if !c.Excludes(from) || !c.Excludes(from.Add(time.Hour)) {
+		t.Error("Time within the event duration was not excluded")
+	}
+	if c.Excludes(from.Add(2 * time.Hour)) {
+		t.Error("End of the event duration was excluded")
+	}
+}
+
+func TestParseICalDuration(t *testing.T) {
+	for _, test := range []struct {
+		value string
+		days  int
+		d     time.Duration
+	}{
+		{"P1D", 1, 0},
+		{"P2W", 14, 0},
+		{"PT1H30M", 0, 90 * time.Minute},
+		{"P1DT12H", 1, 12 * time.Hour},
+		{"PT15S", 0, 15 * time.Second},
+	} {
+		days, d, err := parseICalDuration(test.value)
+		if err != nil || days != test.days || d != test.d {
+			t.Errorf("Duration %s did not match. Got %d days, %v and %v, expected %d days and %v", test.value, days, d, err, test.days, test.d)
+		}
+	}
+	for _, value := range []string{"", "P", "1D", "PT", "P1DT", "P1H", "PT1D", "PD", "P1D2"} {
+		if _, _, err := parseICalDuration(value); err == nil {
+			t.Errorf("Expected duration %q to return an error", value)
+		}
+	}
+}
+
+func TestLoadICSError(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		r    io.Reader
+	}{
+		{"invalid DTSTART", strings.NewReader("BEGIN:VEVENT\nDTSTART:2028\nEND:VEVENT\n")},
+		{"DTSTART without a value", strings.NewReader("BEGIN:VEVENT\nDTSTART;VALUE=DATE\nEND:VEVENT\n")},
+		{"DTEND without a value", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nDTEND\nEND:VEVENT\n")},
+		{"an over-long line", strings.NewReader("BEGIN:VEVENT\nSUMMARY:" + strings.Repeat("x", 100000) + "\nEND:VEVENT\n")},
+		{"a read error", iotest.ErrReader(errors.New("test"))},
+		{"an invalid RRULE", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nRRULE:FREQ=SOMETIMES\nEND:VEVENT\n")},
+		{"two RRULEs", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nRRULE:FREQ=DAILY\nRRULE:FREQ=WEEKLY\nEND:VEVENT\n")},
+		{"an invalid EXDATE", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nRRULE:FREQ=DAILY\nEXDATE:2028\nEND:VEVENT\n")},
+		{"an RDATE", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nRDATE:20280301\nEND:VEVENT\n")},
+		{"an EXRULE", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nEXRULE:FREQ=DAILY\nEND:VEVENT\n")},
+		{"an invalid DURATION", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nDURATION:1D\nEND:VEVENT\n")},
+		{"both DTEND and DURATION", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280101\nDTEND:20280102\nDURATION:P1D\nEND:VEVENT\n")},
+		{"an end before the start", strings.NewReader("BEGIN:VEVENT\nDTSTART:20280102\nDTEND:20280101\nEND:VEVENT\n")},
+	} {
+		if _, err := LoadICS(test.r); err == nil {
+			t.Errorf("LoadICS did not fail with %s", test.name)
+		}
+	}
+}
+
+func TestUnion(t *testing.T) {
+	sat := time.Date(2028, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := Union(
+		NewHolidayCalendar().ExcludeWeekday(time.Saturday),
+		NewHolidayCalendar().ExcludeDate(sat.AddDate(0, 0, 2)),
+	)
+	if !c.Excludes(sat) || !c.Excludes(sat.AddDate(0, 0, 2)) {
+		t.Error("Union did not exclude times excluded by either calendar")
+	}
+	if c.Excludes(sat.AddDate(0, 0, 1)) {
+		t.Error("Union excluded a time excluded by neither calendar")
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	sat := time.Date(2028, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := Intersection(
+		NewHolidayCalendar().ExcludeWeekday(time.Saturday, time.Sunday),
+		NewHolidayCalendar().ExcludeDate(sat).ExcludeDate(sat.AddDate(0, 0, 2)),
+	)
+	if !c.Excludes(sat) {
+		t.Error("Intersection did not exclude a time excluded by both calendars")
+	}
+	if c.Excludes(sat.AddDate(0, 0, 1)) || c.Excludes(sat.AddDate(0, 0, 2)) {
+		t.Error("Intersection excluded a time excluded by only one calendar")
+	}
+	if Intersection().Excludes(sat) {
+		t.Error("Empty intersection excluded a time")
+	}
+}
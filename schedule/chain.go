@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Then sets the job to run after every successful run of this job, receiving
+// the return values of this job as its arguments. If the last return value
+// of this job is an error, it is not passed along.
+// The chained run is requested when this job is run by a Queue, through the
+// same path as Trigger. If the next job has not been added to a queue, it is
+// added to the queue running this job.
+// If the function of the next job cannot be called with the return values
+// of this job, an error is returned and the chain is not changed.
+func (j *Job) Then(next *Job) error {
+	var outs []reflect.Type
+	t := j.function.Type()
+	for i := 0; i < t.NumOut(); i++ {
+		if i < t.NumOut()-1 || t.Out(i) != errorType {
+			outs = append(outs, t.Out(i))
+		}
+	}
+	if !accepts(next.function.Type(), outs) {
+		return fmt.Errorf("schedule: job %s cannot be called with the results of job %s %v", next.Name, j.Name, outs)
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.then = next
+	return nil
+}
+
+// OnFailure sets the job to run after every failed run of this job,
+// receiving the error of the run as its only argument. See Then.
+// A run fails when the function panics, or when it returns a non-nil error
+// and FailOnError is set.
+// If the function of the job cannot be called with an error, an error is
+// returned and the chain is not changed.
+func (j *Job) OnFailure(job *Job) error {
+	if !accepts(job.function.Type(), []reflect.Type{errorType}) {
+		return fmt.Errorf("schedule: job %s cannot be called with the error of job %s", job.Name, j.Name)
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.failed = job
+	return nil
+}
+
+// successor returns the job chained to a run of this job with the given
+// results and error, along with its arguments. If no job is chained, nil is
+// returned.
+func (j *Job) successor(result []interface{}, err error) (*Job, []interface{}) {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	if err != nil {
+		return j.failed, []interface{}{err}
+	}
+	if t := j.function.Type(); t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType && len(result) > 0 {
+		result = result[:len(result)-1]
+	}
+	return j.then, result
+}
+
+// accepts returns whether a function of type fn can be called with
+// arguments of the given types.
+func accepts(fn reflect.Type, args []reflect.Type) bool {
+	n := fn.NumIn()
+	if fn.IsVariadic() {
+		n--
+		if len(args) < n {
+			return false
+		}
+		for _, arg := range args[n:] {
+			if !arg.AssignableTo(fn.In(n).Elem()) {
+				return false
+			}
+		}
+	} else if len(args) != n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if !args[i].AssignableTo(fn.In(i)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// drain runs the queue until n results have been emitted or a second has
+// passed.
+func drain(q *Queue, n int) []JobResult {
+	var results []JobResult
+	for i := 0; i < 100 && len(results) < n; i++ {
+		q.Run()
+		time.Sleep(10 * time.Millisecond)
+		for len(q.results) > 0 {
+			results = append(results, <-q.results)
+		}
+	}
+	return results
+}
+
+func TestJob_Then(t *testing.T) {
+	q := NewQueue()
+	first, err := NewJob("first", func() (string, int, error) { return "count", 2, nil })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	second, err := NewJob("second", func(name string, n int) string {
+		return name + ": " + strconv.Itoa(n)
+	})
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	if err := first.Then(second); err != nil {
+		t.Fatalf("Job errored on Then: %v", err)
+	}
+	q.Add(first)
+	first.Trigger()
+	results := drain(q, 2)
+	if len(results) != 2 {
+		t.Fatalf("Number of results did not match. Got %d, expected 2", len(results))
+	}
+	if results[1].Name != "second" || results[1].Results[0] != "count: 2" {
+		t.Errorf("Chained result did not match. Got %+v", results[1])
+	}
+	if len(q.Jobs) != 2 {
+		t.Errorf("Chained job was not added to the queue. Got %d jobs, expected 2", len(q.Jobs))
+	}
+}
+
+func TestJob_ThenTypeCheck(t *testing.T) {
+	first, err := NewJob("first", func() (string, error) { return "", nil })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	tests := []struct {
+		fun interface{}
+		ok  bool
+	}{
+		{func(string) {}, true},
+		{func(interface{}) {}, true},
+		{func(...string) {}, true},
+		{func(string, ...int) {}, true},
+		{func(int) {}, false},
+		{func() {}, false},
+		{func(string, error) {}, false},
+	}
+	for _, test := range tests {
+		next, err := NewJob("next", test.fun)
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		if err := first.Then(next); (err == nil) != test.ok {
+			t.Errorf("Then error for %T did not match. Got %v, expected success %v", test.fun, err, test.ok)
+		}
+	}
+}
+
+func TestJob_OnFailure(t *testing.T) {
+	q := NewQueue()
+	first, err := NewJob("first", func() (string, error) { return "", errors.New("test") })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	then, err := NewJob("then", func(string) string { return "success" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	failure, err := NewJob("failure", func(err error) string { return "handled " + err.Error() })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	if err := first.Then(then); err != nil {
+		t.Fatalf("Job errored on Then: %v", err)
+	}
+	first.FailOnError()
+	if err := first.OnFailure(failure); err != nil {
+		t.Fatalf("Job errored on OnFailure: %v", err)
+	}
+	q.Add(first)
+	first.Trigger()
+	results := drain(q, 2)
+	if len(results) != 2 || results[1].Name != "failure" || results[1].Results[0] != "handled test" {
+		t.Errorf("Chained results did not match. Got %+v", results)
+	}
+	if then.RunCount() != 0 {
+		t.Errorf("Success job ran after a failure")
+	}
+	bad, err := NewJob("bad", func(string) {})
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	if err := first.OnFailure(bad); err == nil {
+		t.Error("Job did not error on OnFailure with a mismatched function")
+	}
+}
+
+func TestJob_ThenStoreError(t *testing.T) {
+	q := NewQueue()
+	first, err := NewJob("first", func() string { return "first" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	then, err := NewJob("then", func(s string) string { return "after " + s })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	failure, err := NewJob("failure", func(err error) string { return "failure" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	store := &memoryStore{records: make(map[string][]RunRecord)}
+	if err := first.Persist(store); err != nil {
+		t.Fatalf("Job errored on Persist: %v", err)
+	}
+	store.err = errors.New("test")
+	if err := first.Then(then); err != nil {
+		t.Fatalf("Job errored on Then: %v", err)
+	}
+	if err := first.OnFailure(failure); err != nil {
+		t.Fatalf("Job errored on OnFailure: %v", err)
+	}
+	q.Add(first)
+	first.Trigger()
+	results := drain(q, 2)
+	if len(results) != 2 || results[1].Name != "then" || results[1].Results[0] != "after first" {
+		t.Errorf("Chained results did not match. Got %+v", results)
+	}
+	if failure.RunCount() != 0 {
+		t.Error("Failure job ran after a successful run whose record could not be saved")
+	}
+	select {
+	case <-q.Errors():
+	default:
+		t.Error("Expected the store error to be emitted")
+	}
+}
@@ -0,0 +1,159 @@
+package schedule
+
+import "time"
+
+// An Edge represents the edges of an interval on which Throttle fires.
+type Edge int
+
+const (
+	// LeadingEdge fires on the first event of an interval.
+	LeadingEdge Edge = 1 << iota
+	// TrailingEdge fires at the end of an interval if any events were
+	// received during it.
+	TrailingEdge
+)
+
+// Debounce returns a channel receiving a single value once no value has been
+// received on ch for the quiet period, coalescing bursts of events, e.g.
+// Job.TriggerOn(Debounce(ch, time.Second)).
+// The returned channel is closed after ch is closed, once any pending value
+// has been sent.
+func Debounce(ch <-chan struct{}, quiet time.Duration) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	go func() {
+		defer close(out)
+		var timer *time.Timer
+		var fire <-chan time.Time
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					if fire != nil {
+						signal(out)
+					}
+					return
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.NewTimer(quiet)
+				fire = timer.C
+			case <-fire:
+				signal(out)
+				fire = nil
+			}
+		}
+	}()
+	return out
+}
+
+// Throttle returns a channel receiving at most one value per interval for
+// the values received on ch. With LeadingEdge, a value is sent as soon as the
+// first event of an interval is received. With TrailingEdge, a value is sent
+// at the end of an interval in which events were received, starting a new
+// interval. Both edges may be combined, e.g. LeadingEdge|TrailingEdge.
+// If no edge is provided, LeadingEdge is used.
+// The returned channel is closed after ch is closed, once any pending
+// trailing value has been sent.
+func Throttle(ch <-chan struct{}, interval time.Duration, edge Edge) <-chan struct{} {
+	if edge&(LeadingEdge|TrailingEdge) == 0 {
+		edge = LeadingEdge
+	}
+	out := make(chan struct{}, 1)
+	go func() {
+		defer close(out)
+		var cooldown <-chan time.Time
+		pending := false
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					if pending {
+						signal(out)
+					}
+					return
+				}
+				if cooldown != nil {
+					pending = edge&TrailingEdge != 0
+					continue
+				}
+				if edge&LeadingEdge != 0 {
+					signal(out)
+				} else {
+					pending = true
+				}
+				cooldown = time.After(interval)
+			case <-cooldown:
+				cooldown = nil
+				if pending {
+					signal(out)
+					pending = false
+					cooldown = time.After(interval)
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Batch returns a channel receiving the values received on ch in batches of
+// up to size values. A batch is sent once it holds size values, or once wait
+// has passed since its first value was received, whichever comes first.
+// If size is 0 or negative, batches are only limited by wait, and if wait is
+// 0 or negative, batches are only limited by size. If both are 0 or
+// negative, size will be set to 1 and every value is sent in its own batch.
+// The returned channel is closed after ch is closed, once any pending batch
+// has been sent.
+func Batch(ch <-chan interface{}, size int, wait time.Duration) <-chan []interface{} {
+	if size <= 0 && wait <= 0 {
+		size = 1
+	}
+	out := make(chan []interface{})
+	go func() {
+		defer close(out)
+		var batch []interface{}
+		var timeout <-chan time.Time
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					if len(batch) > 0 {
+						out <- batch
+					}
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && wait > 0 {
+					timeout = time.After(wait)
+				}
+				if size <= 0 || len(batch) < size {
+					continue
+				}
+			case <-timeout:
+			}
+			out <- batch
+			batch, timeout = nil, nil
+		}
+	}()
+	return out
+}
+
+// TriggerOnBatch requests a run of the job for every batch received on ch,
+// until ch is closed. The job function is called with its own arguments
+// followed by the batch as a []interface{}. See Batch and TriggerWith.
+func (j *Job) TriggerOnBatch(ch <-chan []interface{}) {
+	go func() {
+		for batch := range ch {
+			j.TriggerWith(append(j.Args(), batch)...)
+		}
+	}()
+}
+
+// signal sends a value to ch without blocking. If a value is already
+// pending, the new one is coalesced into it.
+func signal(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
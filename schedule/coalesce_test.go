@@ -0,0 +1,154 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// count returns the number of values received on ch until it is closed.
+func count(ch <-chan struct{}) int {
+	n := 0
+	for range ch {
+		n++
+	}
+	return n
+}
+
+func TestDebounce(t *testing.T) {
+	in := make(chan struct{})
+	out := Debounce(in, 30*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		in <- struct{}{}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case <-out:
+		t.Fatal("Debounce fired before the quiet period")
+	default:
+	}
+	time.Sleep(60 * time.Millisecond)
+	select {
+	case <-out:
+	default:
+		t.Fatal("Debounce did not fire after the quiet period")
+	}
+	in <- struct{}{}
+	close(in)
+	if n := count(out); n != 1 {
+		t.Errorf("Number of values after close did not match. Got %d, expected 1", n)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	tests := []struct {
+		edge Edge
+		n    int
+	}{
+		{LeadingEdge, 1},
+		{TrailingEdge, 1},
+		{LeadingEdge | TrailingEdge, 2},
+		{0, 1},
+	}
+	for _, test := range tests {
+		in := make(chan struct{})
+		out := Throttle(in, 50*time.Millisecond, test.edge)
+		for i := 0; i < 5; i++ {
+			in <- struct{}{}
+		}
+		if test.edge != TrailingEdge {
+			select {
+			case <-out:
+			case <-time.After(10 * time.Millisecond):
+				t.Errorf("Throttle with edge %d did not fire on the leading edge", test.edge)
+			}
+			test.n--
+		}
+		time.Sleep(80 * time.Millisecond)
+		close(in)
+		if n := count(out); n != test.n {
+			t.Errorf("Number of values for edge %d did not match. Got %d, expected %d", test.edge, n, test.n)
+		}
+	}
+}
+
+func TestThrottleInterval(t *testing.T) {
+	in := make(chan struct{})
+	out := Throttle(in, 20*time.Millisecond, LeadingEdge)
+	go func() {
+		for i := 0; i < 20; i++ {
+			in <- struct{}{}
+			time.Sleep(5 * time.Millisecond)
+		}
+		close(in)
+	}()
+	if n := count(out); n < 3 || n > 6 {
+		t.Errorf("Number of values did not match. Got %d, expected about 5", n)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	in := make(chan interface{})
+	out := Batch(in, 3, 30*time.Millisecond)
+	go func() {
+		for i := 1; i <= 4; i++ {
+			in <- i
+		}
+		time.Sleep(60 * time.Millisecond)
+		in <- 5
+		close(in)
+	}()
+	expected := [][]interface{}{{1, 2, 3}, {4}, {5}}
+	var batches [][]interface{}
+	for batch := range out {
+		batches = append(batches, batch)
+	}
+	if !reflect.DeepEqual(batches, expected) {
+		t.Errorf("Batches did not match. Got %v, expected %v", batches, expected)
+	}
+}
+
+func TestBatchUnlimited(t *testing.T) {
+	in := make(chan interface{})
+	out := Batch(in, 0, 0)
+	go func() {
+		for i := 1; i <= 2; i++ {
+			in <- i
+		}
+	}()
+	for i := 1; i <= 2; i++ {
+		select {
+		case batch := <-out:
+			if !reflect.DeepEqual(batch, []interface{}{i}) {
+				t.Errorf("Batch did not match. Got %v, expected %v", batch, []interface{}{i})
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected every value to be sent in its own batch")
+		}
+	}
+	close(in)
+}
+
+func TestJob_TriggerOnBatch(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func(batch []interface{}) int { return len(batch) })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	q.Add(j)
+	in := make(chan interface{})
+	j.TriggerOnBatch(Batch(in, 2, 0))
+	in <- "a"
+	in <- "b"
+	close(in)
+	for i := 0; i < 100 && len(q.results) == 0; i++ {
+		q.Run()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(q.results) != 1 {
+		t.Fatalf("Number of results in buffer did not match. Got %d, expected 1.", len(q.results))
+	}
+	if res := <-q.results; res.Results[0] != 2 {
+		t.Errorf("Job result did not match. Got %v, expected 2", res.Results[0])
+	}
+}
@@ -0,0 +1,170 @@
+package schedule
+
+import "time"
+
+// maxCompositeRuns is the number of candidate times of the underlying
+// triggers examined by a composite trigger before giving up. Composite
+// triggers leap from one candidate to the next rather than stepping through
+// every scheduled time, so the limit is only reached when the underlying
+// triggers practically never agree.
+const maxCompositeRuns = 10000
+
+// anyOf is a recurrence firing whenever any of its triggers fire.
+type anyOf []*Trigger
+
+func (a anyOf) next(start, after time.Time) time.Time {
+	var next time.Time
+	for _, t := range a {
+		n := t.NextAfter(after)
+		if !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// allOf is a recurrence firing only when all of its triggers fire at the
+// same time.
+type allOf []*Trigger
+
+func (a allOf) next(start, after time.Time) time.Time {
+	if len(a) == 0 {
+		return time.Time{}
+	}
+	// No trigger fires before the latest of the next times of all triggers,
+	// so the search leaps straight to it until they agree.
+	for i := 0; i < maxCompositeRuns; i++ {
+		var latest time.Time
+		same := true
+		for _, t := range a {
+			n := t.NextAfter(after)
+			if n.IsZero() {
+				return time.Time{}
+			}
+			if !latest.IsZero() && !n.Equal(latest) {
+				same = false
+			}
+			if n.After(latest) {
+				latest = n
+			}
+		}
+		if same {
+			return latest
+		}
+		after = latest.Add(-time.Nanosecond)
+	}
+	return time.Time{}
+}
+
+// offset is a recurrence firing a fixed duration after its trigger.
+type offset struct {
+	trigger *Trigger
+	d       time.Duration
+}
+
+func (o offset) next(start, after time.Time) time.Time {
+	n := o.trigger.NextAfter(after.Add(-o.d))
+	if n.IsZero() {
+		return n
+	}
+	return n.Add(o.d)
+}
+
+// window is a recurrence firing whenever its trigger fires within a daily
+// time window.
+type window struct {
+	trigger  *Trigger
+	from, to time.Duration
+}
+
+func (w window) next(start, after time.Time) time.Time {
+	for i := 0; i < maxCompositeRuns; i++ {
+		n := w.trigger.NextAfter(after)
+		if n.IsZero() || w.contains(n) {
+			return n
+		}
+		// Leap to the opening of the next window rather than stepping
+		// through the times of the trigger outside of it.
+		after = w.opening(n).Add(-time.Nanosecond)
+	}
+	return time.Time{}
+}
+
+// opening returns the time the next window opens after tm, which is outside
+// of the window.
+func (w window) opening(tm time.Time) time.Time {
+	y, m, d := tm.Date()
+	h, min, sec := int(w.from/time.Hour), int(w.from%time.Hour/time.Minute), int(w.from%time.Minute/time.Second)
+	open := time.Date(y, m, d, h, min, sec, 0, tm.Location())
+	if !open.After(tm) {
+		open = time.Date(y, m, d+1, h, min, sec, 0, tm.Location())
+	}
+	return open
+}
+
+// contains returns whether the time of day of tm, in its own location, is
+// within the window. If from is after to, the window spans midnight.
+func (w window) contains(tm time.Time) bool {
+	d := clockOffset(tm)
+	if w.from <= w.to {
+		return d >= w.from && d < w.to
+	}
+	return d >= w.from || d < w.to
+}
+
+// AnyOf creates a Trigger firing at the earliest next time of any of the
+// given triggers.
+func AnyOf(triggers ...*Trigger) *Trigger {
+	t := NewTrigger()
+	t.rule = anyOf(triggers)
+	return t
+}
+
+// AllOf creates a Trigger firing only at the times all of the given triggers
+// fire at.
+func AllOf(triggers ...*Trigger) *Trigger {
+	t := NewTrigger()
+	t.rule = allOf(triggers)
+	return t
+}
+
+// Offset creates a Trigger firing d after every time the given trigger
+// fires. A negative d fires before it.
+func Offset(trigger *Trigger, d time.Duration) *Trigger {
+	t := NewTrigger()
+	t.rule = offset{trigger, d}
+	return t
+}
+
+// Except creates a Trigger firing whenever the given trigger fires, skipping
+// the times excluded by the given calendar.
+func Except(trigger *Trigger, cal Calendar) *Trigger {
+	return AnyOf(trigger).Exclude(cal)
+}
+
+// Window creates a Trigger firing whenever the given trigger fires between
+// two times of day. The values passed need to be in 24-hour "15:04" format,
+// with the window including from and excluding to. If from is after to, the
+// window spans midnight.
+// E.g. Window(NewTrigger().Every("15m"), "09:00", "17:00").
+// If the provided strings cannot be parsed, the function will panic with
+// the error.
+func Window(trigger *Trigger, from, to string) *Trigger {
+	f, err := time.Parse("15:04", from)
+	if err != nil {
+		panic(err)
+	}
+	e, err := time.Parse("15:04", to)
+	if err != nil {
+		panic(err)
+	}
+	t := NewTrigger()
+	t.rule = window{trigger, clockOffset(f), clockOffset(e)}
+	return t
+}
+
+// clockOffset returns the duration since midnight of the time of day of tm.
+func clockOffset(tm time.Time) time.Duration {
+	h, m, s := tm.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
@@ -0,0 +1,132 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnyOf(t *testing.T) {
+	start := time.Date(2028, 1, 3, 8, 0, 0, 0, time.UTC)
+	trigger := AnyOf(
+		NewTrigger().From(start).Every("15m"),
+		NewTrigger().From(start).Each(1).Day().AtClock("00:00"),
+	)
+	next := start.Add(15 * time.Minute)
+	if n := trigger.NextAfter(start); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+	if n := AnyOf().NextAfter(start); !n.IsZero() {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, time.Time{})
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	start := time.Date(2028, 1, 3, 8, 0, 0, 0, time.UTC)
+	trigger := AllOf(
+		NewTrigger().From(start).Every("15m"),
+		NewTrigger().From(start).Every("20m"),
+	)
+	next := start.Add(time.Hour)
+	if n := trigger.NextAfter(start); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+	if n := AllOf().NextAfter(start); !n.IsZero() {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, time.Time{})
+	}
+}
+
+func TestOffset(t *testing.T) {
+	start := time.Date(2028, 1, 3, 8, 0, 0, 0, time.UTC)
+	trigger := Offset(NewTrigger().From(start).Every("1h"), 5*time.Minute)
+	tests := []struct {
+		after time.Time
+		next  time.Time
+	}{
+		{start, start.Add(65 * time.Minute)},
+		{start.Add(62 * time.Minute), start.Add(65 * time.Minute)},
+		{start.Add(65 * time.Minute), start.Add(125 * time.Minute)},
+	}
+	for _, test := range tests {
+		if n := trigger.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time did not match. Got %v, expected %v", n, test.next)
+		}
+	}
+}
+
+func TestExcept(t *testing.T) {
+	// 2028-01-01 is a Saturday.
+	start := time.Date(2027, 12, 31, 9, 0, 0, 0, time.UTC)
+	weekends := NewHolidayCalendar().ExcludeWeekday(time.Saturday, time.Sunday)
+	trigger := Except(NewTrigger().From(start).Each(1).Day(), weekends)
+	next := time.Date(2028, 1, 3, 9, 0, 0, 0, time.UTC)
+	if n := trigger.NextAfter(start); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	start := time.Date(2028, 1, 3, 8, 0, 0, 0, time.UTC)
+	trigger := Window(NewTrigger().From(start).Every("15m"), "09:00", "17:00")
+	tests := []struct {
+		after time.Time
+		next  time.Time
+	}{
+		{start, start.Add(time.Hour)},
+		{start.Add(8*time.Hour + 30*time.Minute), start.Add(8*time.Hour + 45*time.Minute)},
+		{start.Add(8*time.Hour + 45*time.Minute), start.Add(25 * time.Hour)},
+	}
+	for _, test := range tests {
+		if n := trigger.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time did not match. Got %v, expected %v", n, test.next)
+		}
+	}
+}
+
+func TestWindowMidnight(t *testing.T) {
+	start := time.Date(2028, 1, 3, 20, 0, 0, 0, time.UTC)
+	trigger := Window(NewTrigger().From(start).Every("1h"), "23:00", "02:00")
+	tests := []struct {
+		after time.Time
+		next  time.Time
+	}{
+		{start, start.Add(3 * time.Hour)},
+		{start.Add(5 * time.Hour), start.Add(27 * time.Hour)},
+	}
+	for _, test := range tests {
+		if n := trigger.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time did not match. Got %v, expected %v", n, test.next)
+		}
+	}
+}
+
+func TestWindowDense(t *testing.T) {
+	start := time.Date(2028, 1, 3, 0, 0, 0, 0, time.UTC)
+	window := Window(NewTrigger().From(start).Every("1s"), "09:00", "10:00")
+	both := AllOf(NewTrigger().From(start).Every("1s"), window)
+	tests := []struct {
+		after time.Time
+		next  time.Time
+	}{
+		{start.Add(8 * time.Hour), start.Add(9 * time.Hour)},
+		{start.Add(9*time.Hour + 30*time.Minute), start.Add(9*time.Hour + 30*time.Minute + time.Second)},
+		{start.Add(10 * time.Hour), start.Add(33 * time.Hour)},
+		{start.Add(23 * time.Hour), start.Add(33 * time.Hour)},
+	}
+	for _, test := range tests {
+		if n := window.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time did not match. Got %v, expected %v", n, test.next)
+		}
+		if n := both.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time of AllOf did not match. Got %v, expected %v", n, test.next)
+		}
+	}
+}
+
+func TestWindowPanic(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("Window did not panic with an invalid clock string")
+		}
+	}()
+	Window(NewTrigger(), "9am", "17:00")
+}
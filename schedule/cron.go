@@ -0,0 +1,209 @@
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCronDays is the number of days searched for the next matching time of a
+// cronRule before giving up.
+const maxCronDays = 366 * 5
+
+// cronField describes a single field of a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59, nil},
+	{"hour", 0, 23, nil},
+	{"day of month", 1, 31, nil},
+	{"month", 1, 12, []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{"day of week", 0, 7, []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// cronRule is a recurrence following a standard five field cron expression.
+// Each field is stored as a bit set of the matching values.
+type cronRule struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+	loc     *time.Location
+}
+
+// Cron sets the recurrence for the Trigger from a standard five field cron
+// expression: minute, hour, day of month, month and day of week.
+// Fields support "*", values, ranges ("1-5"), steps ("*/15", "0-30/10"),
+// lists ("1,15") and the names of months and weekdays ("JAN", "MON-FRI").
+// If both day fields are restricted, the job runs when either matches.
+// The expression may be prefixed by "CRON_TZ=<location> " to evaluate it in
+// the given location instead of the location of the start time.
+// E.g. Trigger.Cron("30 2 * * MON-FRI").
+// If the provided string cannot be parsed, the function will panic with
+// the error.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) Cron(str string) *Trigger {
+	c, err := parseCron(str)
+	if err != nil {
+		panic(err)
+	}
+	t.interval = 0
+	t.rule = c
+	return t
+}
+
+// parseCron parses a cron expression.
+func parseCron(spec string) (*cronRule, error) {
+	c := &cronRule{}
+	fields, offsets := cronTokens(spec)
+	if len(fields) > 0 && strings.HasPrefix(strings.ToUpper(fields[0]), "CRON_TZ=") {
+		name := fields[0][len("CRON_TZ="):]
+		loc, err := time.LoadLocation(name)
+		if err != nil || name == "" {
+			return nil, &ParseError{spec, offsets[0] + len("CRON_TZ="), "a known time zone", ErrInvalidCron}
+		}
+		c.loc = loc
+		fields, offsets = fields[1:], offsets[1:]
+	}
+	if len(fields) != len(cronFields) {
+		pos := len(spec)
+		if len(fields) > len(cronFields) {
+			pos = offsets[len(cronFields)]
+		}
+		return nil, &ParseError{spec, pos, "5 fields: minute, hour, day of month, month and day of week", ErrInvalidCron}
+	}
+	sets := make([]uint64, len(fields))
+	for i, field := range fields {
+		set, err := cronFields[i].parse(spec, offsets[i], field)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	c.minute, c.hour, c.dom, c.month, c.dow = sets[0], sets[1], sets[2], sets[3], sets[4]
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domStar = strings.HasPrefix(fields[2], "*")
+	c.dowStar = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// cronTokens splits a cron expression into fields, returning the byte offset
+// of every field.
+func cronTokens(spec string) ([]string, []int) {
+	var fields []string
+	var offsets []int
+	start := -1
+	for i, r := range spec + " " {
+		if r == ' ' || r == '\t' || r == '\n' {
+			if start >= 0 {
+				fields = append(fields, spec[start:i])
+				offsets = append(offsets, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return fields, offsets
+}
+
+// parse parses a single cron field found at pos in spec into a bit set.
+func (f cronField) parse(spec string, pos int, field string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		expected := f.name + " value (" + strconv.Itoa(f.min) + "-" + strconv.Itoa(f.max) + ")"
+		fail := &ParseError{spec, pos, expected, ErrInvalidCron}
+		pos += len(part) + 1
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				fail.Pos += i + 1
+				fail.Expected = "a positive step"
+				return 0, fail
+			}
+			rng, step = part[:i], n
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var ok bool
+			if lo, ok = f.value(bounds[0]); !ok {
+				return 0, fail
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, ok = f.value(bounds[1]); !ok || hi < lo {
+					fail.Pos += len(bounds[0]) + 1
+					return 0, fail
+				}
+			} else if step > 1 {
+				hi = f.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// value parses a single value or name of the field.
+func (f cronField) value(str string) (int, bool) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(str, name) {
+			return i, true
+		}
+	}
+	v, err := strconv.Atoi(str)
+	return v, err == nil && v >= f.min && v <= f.max
+}
+
+func (c *cronRule) next(start, after time.Time) time.Time {
+	loc := c.loc
+	if loc == nil {
+		loc = start.Location()
+	}
+	if start.After(after) {
+		after = start.Add(-time.Nanosecond)
+	}
+	t := after.In(loc).Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(0, 0, maxCronDays)
+	for t.Before(end) {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay returns whether the date of tm matches the day fields.
+// If both day fields are restricted, either may match.
+func (c *cronRule) matchesDay(tm time.Time) bool {
+	dom := c.dom&(1<<uint(tm.Day())) != 0
+	dow := c.dow&(1<<uint(tm.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrigger_Cron(t *testing.T) {
+	// 2028-01-03 is a Monday.
+	start := time.Date(2028, 1, 3, 8, 7, 30, 0, time.UTC)
+	tests := []struct {
+		spec  string
+		after time.Time
+		next  time.Time
+	}{
+		{"* * * * *", start, time.Date(2028, 1, 3, 8, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", start, time.Date(2028, 1, 3, 8, 15, 0, 0, time.UTC)},
+		{"30 2 * * MON-FRI", start, time.Date(2028, 1, 4, 2, 30, 0, 0, time.UTC)},
+		{"30 2 * * mon-fri", time.Date(2028, 1, 7, 3, 0, 0, 0, time.UTC), time.Date(2028, 1, 10, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", start, time.Date(2028, 1, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2028, 1, 3, 13, 0, 0, 0, time.UTC), time.Date(2028, 1, 3, 17, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", start, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", start, time.Date(2028, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", start, time.Date(2028, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", start, time.Date(2028, 1, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * JAN,JUL *", time.Date(2028, 1, 31, 12, 0, 0, 0, time.UTC), time.Date(2028, 7, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", start, time.Time{}},
+	}
+	for _, test := range tests {
+		trigger := NewTrigger().From(start).Cron(test.spec)
+		if n := trigger.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time for %q did not match. Got %v, expected %v", test.spec, n, test.next)
+		}
+	}
+}
+
+func TestTrigger_CronTZ(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skipf("Could not load time zone: %v", err)
+	}
+	start := time.Date(2028, 1, 3, 0, 0, 0, 0, time.UTC)
+	trigger := NewTrigger().From(start).Cron("CRON_TZ=Europe/Oslo 30 2 * * *")
+	next := time.Date(2028, 1, 3, 2, 30, 0, 0, loc)
+	if n := trigger.NextAfter(start); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+}
+
+func TestTrigger_CronPanic(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * FOO *",
+		"CRON_TZ=Nowhere/Nothing * * * * *",
+	}
+	for _, spec := range invalid {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Errorf("Cron did not panic with invalid spec %q", spec)
+				}
+			}()
+			NewTrigger().Cron(spec)
+		}()
+	}
+}
@@ -0,0 +1,490 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrUnsupportedLocale is returned when describing a Trigger in a locale
+// without translations.
+var ErrUnsupportedLocale = errors.New("schedule: unsupported locale")
+
+// describeLayout is the layout used when describing a point in time.
+const describeLayout = "Mon 2 Jan 2006 15:04"
+
+// nextLayout is the layout used when describing the next run of a job.
+const nextLayout = "Mon 2 Jan 15:04"
+
+var ordinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+// Describe returns a human-readable description of the Trigger in the given
+// locale, e.g. "Every weekday at 02:30 (Europe/Oslo)".
+// Only English ("en" and its regional variants) is currently supported. An
+// empty locale is treated as English.
+func (t *Trigger) Describe(locale string) (string, error) {
+	l := strings.ToLower(locale)
+	if l != "" && l != "en" && !strings.HasPrefix(l, "en-") && !strings.HasPrefix(l, "en_") {
+		return "", ErrUnsupportedLocale
+	}
+	desc := t.describe()
+	if desc == "" {
+		return desc, nil
+	}
+	r := []rune(desc)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r), nil
+}
+
+// String returns an English description of the Trigger. See Describe.
+func (t *Trigger) String() string {
+	desc, _ := t.Describe("en")
+	return desc
+}
+
+// describe returns an English description of the Trigger, in lower case.
+func (t *Trigger) describe() string {
+	var desc string
+	switch {
+	case t.rule != nil:
+		desc = t.rule.describe(t.start)
+	case t.interval > 0:
+		desc = "every " + describeDuration(t.interval)
+		if t.delayed {
+			desc += " after the previous run completes"
+		}
+	default:
+		return "not scheduled"
+	}
+	if t.start.After(time.Now()) {
+		desc += ", starting " + t.start.Format(describeLayout)
+	}
+	if !t.until.IsZero() {
+		desc += ", until " + t.until.Format(describeLayout)
+	}
+	if t.limit > 0 {
+		runs := plural(int(t.limit), "run")
+		if t.successful {
+			runs = plural(int(t.limit), "successful run")
+		}
+		desc += ", limited to " + runs
+	}
+	if t.excluded != nil {
+		if t.shift {
+			desc += ", moving excluded days to the next available day"
+		} else {
+			desc += ", skipping excluded times"
+		}
+	}
+	if t.splay > 0 {
+		desc += ", delayed by " + describeDuration(t.splay)
+	}
+	if t.jitter > 0 {
+		desc += ", with up to " + describeDuration(t.jitter) + " of jitter"
+	}
+	return desc
+}
+
+// String returns a summary of the job, including its queues, state, schedule
+// and next run.
+func (j *Job) String() string {
+	j.mutex.RLock()
+	trigger, queues := j.trigger, j.queues
+	j.mutex.RUnlock()
+	names := make([]string, len(queues))
+	for i, q := range queues {
+		names[i] = q.label()
+	}
+	queue := "no queue"
+	if len(names) > 0 {
+		queue = "queue " + strings.Join(names, ", ")
+	}
+	next := "none"
+	if n := j.NextRun(); !n.IsZero() {
+		next = n.Format(nextLayout)
+	}
+	return fmt.Sprintf("%s (%s, %s): %s, next: %s", j.Name, queue, j.State(), trigger, next)
+}
+
+func (l timeList) describe(start time.Time) string {
+	switch {
+	case len(l) == 0:
+		return "never"
+	case len(l) == 1:
+		return "once at " + l[0].Format(describeLayout)
+	case len(l) <= 3:
+		times := make([]string, len(l))
+		for i, tm := range l {
+			times[i] = tm.Format(describeLayout)
+		}
+		return "at " + describeList(times)
+	}
+	return fmt.Sprintf("at %d fixed times from %s to %s", len(l),
+		l[0].Format(describeLayout), l[len(l)-1].Format(describeLayout))
+}
+
+func (d delay) describe(start time.Time) string {
+	return "once at " + start.Add(time.Duration(d)).Format(describeLayout)
+}
+
+func (c *calendarRule) describe(start time.Time) string {
+	clock := start.Format("15:04")
+	if c.clock {
+		clock = fmt.Sprintf("%02d:%02d", c.hour, c.minute)
+	}
+	var desc string
+	switch c.unit {
+	case unitDay:
+		desc = describeEvery(c.n, "day")
+		if isWeekdays(c.weekdays) && c.n == 1 {
+			desc = "every weekday"
+		} else if len(c.weekdays) > 0 {
+			desc += " on " + describeWeekdays(c.weekdays)
+		}
+	case unitWeek:
+		days := c.weekdays
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		switch {
+		case c.n == 1 && isWeekdays(days):
+			desc = "every weekday"
+		case c.n == 1:
+			desc = "every " + describeWeekdays(days)
+		default:
+			desc = describeEvery(c.n, "week") + " on " + describeWeekdays(days)
+		}
+	case unitMonth:
+		desc = describeEvery(c.n, "month")
+		switch {
+		case c.nth != 0:
+			desc += " on the " + describeNth(c.nth) + " " + c.nthDay.String()
+		case c.monthday < 0:
+			desc += " on the last day"
+		case c.monthday > 0:
+			desc += " on the " + describeOrdinal(c.monthday)
+		case len(c.weekdays) > 0:
+			desc += " on every " + describeWeekdays(c.weekdays)
+		default:
+			desc += " on the " + describeOrdinal(start.Day())
+		}
+	}
+	return desc + " at " + clock + describeLocation(start.Location())
+}
+
+func (c *cronRule) describe(start time.Time) string {
+	minutes, hours := bits(c.minute, 0, 59), bits(c.hour, 0, 23)
+	allMinutes, allHours := len(minutes) == 60, len(hours) == 24
+	var timeDesc string
+	switch {
+	case allMinutes && allHours:
+		timeDesc = "every minute"
+	case len(minutes) == 1 && len(hours) == 1:
+		timeDesc = fmt.Sprintf("at %02d:%02d", hours[0], minutes[0])
+	case len(minutes) == 1 && allHours:
+		timeDesc = fmt.Sprintf("every hour at minute %d", minutes[0])
+	case step(minutes, 60) > 0 && allHours:
+		timeDesc = "every " + plural(step(minutes, 60), "minute")
+	case step(minutes, 60) > 0:
+		timeDesc = "every " + plural(step(minutes, 60), "minute") + " during hours " + describeInts(hours)
+	case len(minutes)*len(hours) <= 4:
+		var times []string
+		for _, h := range hours {
+			for _, m := range minutes {
+				times = append(times, fmt.Sprintf("%02d:%02d", h, m))
+			}
+		}
+		timeDesc = "at " + describeList(times)
+	default:
+		timeDesc = "at minutes " + describeInts(minutes) + " past hours " + describeInts(hours)
+	}
+	var weekdays []time.Weekday
+	for _, d := range bits(c.dow, 0, 6) {
+		weekdays = append(weekdays, time.Weekday(d))
+	}
+	domDesc := "on day " + describeInts(bits(c.dom, 1, 31)) + " of the month"
+	var desc string
+	switch {
+	case c.domStar && c.dowStar:
+		desc = timeDesc
+		if !strings.HasPrefix(timeDesc, "every") {
+			desc = "every day " + timeDesc
+		}
+	case c.domStar && isWeekdays(weekdays):
+		desc = "every weekday " + timeDesc
+	case c.domStar:
+		desc = "every " + describeWeekdays(weekdays) + " " + timeDesc
+	case c.dowStar:
+		desc = domDesc + " " + timeDesc
+	default:
+		desc = domDesc + " and every " + describeWeekdays(weekdays) + " " + timeDesc
+	}
+	if months := bits(c.month, 1, 12); len(months) < 12 {
+		names := make([]string, len(months))
+		for i, m := range months {
+			names[i] = time.Month(m).String()
+		}
+		desc += " in " + describeList(names)
+	}
+	loc := start.Location()
+	if c.loc != nil {
+		loc = c.loc
+	}
+	return desc + describeLocation(loc)
+}
+
+func (r *rrule) describe(start time.Time) string {
+	units := []string{"year", "month", "week", "day", "hour", "minute"}
+	desc := describeEvery(r.interval, units[r.freq])
+	if len(r.byMonth) > 0 {
+		names := make([]string, len(r.byMonth))
+		for i, m := range r.byMonth {
+			names[i] = time.Month(m).String()
+		}
+		desc += " in " + describeList(names)
+	}
+	if len(r.byMonthDay) > 0 {
+		days := make([]string, len(r.byMonthDay))
+		for i, d := range r.byMonthDay {
+			days[i] = describeOrdinal(d)
+		}
+		desc += " on the " + describeList(days)
+	}
+	if len(r.byDay) > 0 {
+		days := make([]string, len(r.byDay))
+		for i, d := range r.byDay {
+			days[i] = d.day.String()
+			if d.n != 0 {
+				days[i] = "the " + describeNth(d.n) + " " + days[i]
+			}
+		}
+		desc += " on " + describeList(days)
+	}
+	if len(r.bySetPos) > 0 {
+		positions := make([]string, len(r.bySetPos))
+		for i, p := range r.bySetPos {
+			positions[i] = describeNth(p)
+		}
+		desc += ", taking the " + describeList(positions) + " of each " + units[r.freq]
+	}
+	if len(r.byHour) > 0 || len(r.byMinute) > 0 {
+		hours := "every hour"
+		if len(r.byHour) > 0 {
+			hours = "hours " + describeInts(r.byHour)
+		}
+		minutes := fmt.Sprint(start.Minute())
+		if len(r.byMinute) > 0 {
+			minutes = describeInts(r.byMinute)
+		}
+		desc += " at minutes " + minutes + " past " + hours
+	}
+	if r.count > 0 {
+		desc += ", " + plural(r.count, "time")
+	}
+	if !r.until.IsZero() {
+		desc += ", until " + r.until.Format(describeLayout)
+	}
+	if len(r.exdates) > 0 {
+		desc += ", except " + plural(len(r.exdates), "excluded date")
+	}
+	loc := start.Location()
+	if !r.dtstart.IsZero() {
+		loc = r.dtstart.Location()
+	}
+	return desc + describeLocation(loc)
+}
+
+func (a anyOf) describe(start time.Time) string {
+	return "any of (" + describeTriggers(a) + ")"
+}
+
+func (a allOf) describe(start time.Time) string {
+	return "all of (" + describeTriggers(a) + ")"
+}
+
+func (o offset) describe(start time.Time) string {
+	if o.d < 0 {
+		return describeDuration(-o.d) + " before (" + o.trigger.describe() + ")"
+	}
+	return describeDuration(o.d) + " after (" + o.trigger.describe() + ")"
+}
+
+func (c clockInterval) describe(start time.Time) string {
+	return "every " + describeDuration(c.d)
+}
+
+func (w window) describe(start time.Time) string {
+	return fmt.Sprintf("%s, between %02d:%02d and %02d:%02d", w.trigger.describe(),
+		int(w.from.Hours()), int(w.from.Minutes())%60, int(w.to.Hours()), int(w.to.Minutes())%60)
+}
+
+// describeTriggers returns the descriptions of the triggers separated by
+// semicolons.
+func describeTriggers(triggers []*Trigger) string {
+	descs := make([]string, len(triggers))
+	for i, t := range triggers {
+		descs[i] = t.describe()
+	}
+	return strings.Join(descs, "; ")
+}
+
+// describeDuration returns a description of d, e.g. "1 hour and 30 minutes".
+func describeDuration(d time.Duration) string {
+	if d%time.Second != 0 {
+		return d.String()
+	}
+	var parts []string
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{24 * time.Hour, "day"},
+		{time.Hour, "hour"},
+		{time.Minute, "minute"},
+		{time.Second, "second"},
+	}
+	for _, u := range units {
+		if n := d / u.d; n > 0 {
+			parts = append(parts, plural(int(n), u.name))
+			d -= n * u.d
+		}
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return describeList(parts)
+}
+
+// describeEvery returns e.g. "every day" or "every 2 days".
+func describeEvery(n int, unit string) string {
+	if n == 1 {
+		return "every " + unit
+	}
+	return "every " + plural(n, unit)
+}
+
+// describeLocation returns the name of loc in parentheses, unless it is the
+// local time zone.
+func describeLocation(loc *time.Location) string {
+	if loc == time.Local || loc.String() == "Local" {
+		return ""
+	}
+	return " (" + loc.String() + ")"
+}
+
+// describeList joins items with commas and a final "and".
+func describeList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// describeInts returns a list of values, collapsing consecutive runs of at
+// least three values into ranges, e.g. "1, 9-17 and 20".
+func describeInts(values []int) string {
+	var items []string
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			items = append(items, fmt.Sprintf("%d-%d", values[i], values[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, fmt.Sprint(values[k]))
+			}
+		}
+		i = j + 1
+	}
+	return describeList(items)
+}
+
+// describeWeekdays returns a list of weekday names.
+func describeWeekdays(days []time.Weekday) string {
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = d.String()
+	}
+	return describeList(names)
+}
+
+// describeNth returns e.g. "second" for 2 and "second to last" for -2.
+func describeNth(n int) string {
+	switch {
+	case n == -1:
+		return "last"
+	case n < 0:
+		return describeNth(-n) + " to last"
+	case n < len(ordinals):
+		return ordinals[n]
+	}
+	return describeOrdinal(n)
+}
+
+// describeOrdinal returns e.g. "1st", "22nd" or "last" for -1.
+func describeOrdinal(n int) string {
+	if n < 0 {
+		return describeNth(n) + " day"
+	}
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprint(n) + suffix
+}
+
+// plural returns e.g. "1 minute" or "2 minutes".
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// isWeekdays returns whether days holds exactly Monday through Friday.
+func isWeekdays(days []time.Weekday) bool {
+	var set [7]bool
+	for _, d := range days {
+		set[d] = true
+	}
+	return set == [7]bool{false, true, true, true, true, true, false}
+}
+
+// bits returns the values between min and max set in a bit set.
+func bits(set uint64, min, max int) []int {
+	var values []int
+	for v := min; v <= max; v++ {
+		if set&(1<<uint(v)) != 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// step returns the step of values if they are every step'th value from 0 to
+// below n, e.g. 15 for 0, 15, 30 and 45. Otherwise 0 is returned.
+func step(values []int, n int) int {
+	if len(values) < 2 || values[0] != 0 {
+		return 0
+	}
+	s := values[1]
+	if n%s != 0 || len(values) != n/s {
+		return 0
+	}
+	for i, v := range values {
+		if v != i*s {
+			return 0
+		}
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrigger_Describe(t *testing.T) {
+	// 2028-01-03 is a Monday.
+	start := time.Date(2028, 1, 3, 8, 0, 0, 0, time.Local)
+	// Start times are only described while they are in the future.
+	future, past := time.Now().AddDate(1, 0, 0), time.Now().AddDate(-1, 0, 0)
+	tests := []struct {
+		trigger *Trigger
+		desc    string
+	}{
+		{NewTrigger(), "Not scheduled"},
+		{NewTrigger().Every("15m"), "Every 15 minutes"},
+		{NewTrigger().Every("1h30m"), "Every 1 hour and 30 minutes"},
+		{NewTrigger().EveryAfterCompletion("10m"), "Every 10 minutes after the previous run completes"},
+		{NewTrigger().Every("1h").Limit(3), "Every 1 hour, limited to 3 runs"},
+		{NewTrigger().Every("1h").LimitSuccessful(1), "Every 1 hour, limited to 1 successful run"},
+		{NewTrigger().Every("1h").Jitter(time.Minute), "Every 1 hour, with up to 1 minute of jitter"},
+		{NewTrigger().Cron("30 2 * * MON-FRI"), "Every weekday at 02:30"},
+		{NewTrigger().Cron("CRON_TZ=UTC 30 2 * * MON-FRI"), "Every weekday at 02:30 (UTC)"},
+		{NewTrigger().Cron("*/15 * * * *"), "Every 15 minutes"},
+		{NewTrigger().Cron("* * * * *"), "Every minute"},
+		{NewTrigger().Cron("0 9,17 * * SAT,SUN"), "Every Sunday and Saturday at 09:00 and 17:00"},
+		{NewTrigger().Cron("0 0 1,15 * *"), "On day 1 and 15 of the month at 00:00"},
+		{NewTrigger().Cron("0 12 * JAN,JUL *"), "Every day at 12:00 in January and July"},
+		{NewTrigger().Cron("5 * * * *"), "Every hour at minute 5"},
+		{NewTrigger().From(future).Each(1).Day().AtClock("09:00"), "Every day at 09:00, starting " + future.Format(describeLayout)},
+		{NewTrigger().From(past).Each(1).Day().AtClock("09:00"), "Every day at 09:00"},
+		{NewTrigger().Each(2).Weeks().On(time.Monday, time.Friday).AtClock("17:00"), "Every 2 weeks on Monday and Friday at 17:00"},
+		{NewTrigger().Each(1).Week().On(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday).AtClock("06:00"), "Every weekday at 06:00"},
+		{NewTrigger().Each(1).Month().LastDayOfMonth().AtClock("23:00"), "Every month on the last day at 23:00"},
+		{NewTrigger().Each(3).Months().NthWeekday(2, time.Tuesday).AtClock("10:00"), "Every 3 months on the second Tuesday at 10:00"},
+		{NewTrigger().Each(1).Month().MonthlyOn(22).AtClock("08:15"), "Every month on the 22nd at 08:15"},
+		{NewTrigger().RRule("FREQ=MONTHLY;BYDAY=-1FR;COUNT=5"), "Every month on the last Friday, 5 times"},
+		{NewTrigger().RandomlyEvery("1h"), "Once every 1 hour at a random time"},
+		{NewTrigger().RandomlyBetween("01:00", "05:00"), "Once a day at a random time between 01:00 and 05:00"},
+		{NewTrigger().At(start), "Once at Mon 3 Jan 2028 08:00"},
+		{AnyOf(NewTrigger().Every("1h"), NewTrigger().Cron("0 9 * * *")), "Any of (every 1 hour; every day at 09:00)"},
+		{Window(NewTrigger().Every("30m"), "09:00", "17:00"), "Every 30 minutes, between 09:00 and 17:00"},
+		{Offset(NewTrigger().Cron("0 9 * * *"), -10*time.Minute), "10 minutes before (every day at 09:00)"},
+	}
+	for _, test := range tests {
+		if desc := test.trigger.String(); desc != test.desc {
+			t.Errorf("Trigger description did not match. Got %q, expected %q", desc, test.desc)
+		}
+	}
+}
+
+func TestTrigger_DescribeLocale(t *testing.T) {
+	trigger := NewTrigger().Every("5m")
+	for _, locale := range []string{"", "en", "en-GB", "EN_us"} {
+		if desc, err := trigger.Describe(locale); err != nil || desc != "Every 5 minutes" {
+			t.Errorf("Trigger description did not match for %q. Got %q, %v", locale, desc, err)
+		}
+	}
+	if _, err := trigger.Describe("nb-NO"); !errors.Is(err, ErrUnsupportedLocale) {
+		t.Errorf("Trigger error did not match. Got %v, expected %v", err, ErrUnsupportedLocale)
+	}
+}
+
+func TestJob_String(t *testing.T) {
+	scheduler := NewScheduler()
+	job, _ := NewJob("backup", func() {})
+	job.trigger = NewTrigger().Cron("30 2 * * MON-FRI")
+	scheduler.Add(job)
+	next := job.NextRun().Format("Mon 2 Jan 15:04")
+	expected := "backup (queue default, scheduled): Every weekday at 02:30, next: " + next
+	if str := job.String(); str != expected {
+		t.Errorf("Job string did not match. Got %q, expected %q", str, expected)
+	}
+	job.Disable()
+	if str := job.String(); !strings.Contains(str, "disabled") {
+		t.Errorf("Job string did not contain state. Got %q", str)
+	}
+}
@@ -0,0 +1,188 @@
+package schedule
+
+import "sync"
+
+// slotPool represents the job slots shared by the queues of a Scheduler.
+// Slots are shared between the busy queues in proportion to their weights,
+// after reserving the guaranteed slots of every queue with waiting jobs.
+// A queue may use more than its share as long as no other queue with
+// waiting jobs is below its own.
+type slotPool struct {
+	mutex   sync.Mutex
+	limit   int
+	weights map[string]int
+	min     map[string]int
+	queues  map[*Queue]*queueSlots
+}
+
+// queueSlots represents the slots used by a single queue.
+type queueSlots struct {
+	name    string
+	active  int
+	waiting int
+}
+
+// newSlotPool creates a new slotPool without a limit.
+func newSlotPool() *slotPool {
+	return &slotPool{
+		weights: make(map[string]int),
+		min:     make(map[string]int),
+		queues:  make(map[*Queue]*queueSlots),
+	}
+}
+
+// MaxConcurrent sets the number of jobs run at the same time across all the
+// queues of this Scheduler. The slots are shared fairly between the queues
+// with jobs waiting to be run, in proportion to their weights. See Weight
+// and MinSlots.
+// The limit of each queue still applies. See Queue.MaxConcurrent.
+// If 0 or a negative value is provided, the number of jobs is not limited.
+func (s *Scheduler) MaxConcurrent(n int) {
+	if n < 0 {
+		n = 0
+	}
+	s.slots.mutex.Lock()
+	defer s.slots.mutex.Unlock()
+	s.slots.limit = n
+}
+
+// MinSlots sets the number of slots guaranteed to the given queue when the
+// Scheduler limits the number of jobs run at the same time. While the queue
+// has jobs waiting to be run, other queues may not use its guaranteed slots.
+// See MaxConcurrent.
+func (s *Scheduler) MinSlots(queue string, n int) {
+	if n < 0 {
+		n = 0
+	}
+	s.slots.mutex.Lock()
+	defer s.slots.mutex.Unlock()
+	s.slots.min[queue] = n
+}
+
+// Weight sets the weight of the given queue when the Scheduler limits the
+// number of jobs run at the same time. Busy queues share the slots in
+// proportion to their weights, e.g. a queue with weight 3 gets three times
+// as many slots as a queue with weight 1. See MaxConcurrent.
+// If 0 or a negative value is provided, the weight will be set to 1.
+func (s *Scheduler) Weight(queue string, weight int) {
+	if weight < 1 {
+		weight = 1
+	}
+	s.slots.mutex.Lock()
+	defer s.slots.mutex.Unlock()
+	s.slots.weights[queue] = weight
+}
+
+// slots returns the slots used by the queue, registering it if needed.
+// The caller must hold the pool mutex.
+func (p *slotPool) slots(q *Queue, name string) *queueSlots {
+	s, ok := p.queues[q]
+	if !ok {
+		s = &queueSlots{}
+		p.queues[q] = s
+	}
+	s.name = name
+	return s
+}
+
+// wait records the number of jobs of the queue waiting to be run.
+func (p *slotPool) wait(q *Queue, name string, n int) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.slots(q, name).waiting = n
+}
+
+// acquire takes a slot for a job of the queue, returning false if the queue
+// may not run another job.
+func (p *slotPool) acquire(q *Queue, name string) bool {
+	if p == nil {
+		return true
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	s := p.slots(q, name)
+	if p.limit > 0 && !p.available(s) {
+		return false
+	}
+	s.active++
+	if s.waiting > 0 {
+		s.waiting--
+	}
+	return true
+}
+
+// available returns whether a slot is available to the queue.
+// The caller must hold the pool mutex.
+func (p *slotPool) available(s *queueSlots) bool {
+	free := p.limit
+	for _, o := range p.queues {
+		free -= o.active
+	}
+	if free <= 0 {
+		return false
+	}
+	if s.active < p.min[s.name] {
+		return true
+	}
+	for _, o := range p.queues {
+		if o != s && o.waiting > 0 && o.active < p.min[o.name] {
+			free -= p.min[o.name] - o.active
+		}
+	}
+	if free <= 0 {
+		return false
+	}
+	if float64(s.active) < p.share(s) {
+		return true
+	}
+	for _, o := range p.queues {
+		if o != s && o.waiting > 0 && float64(o.active) < p.share(o) {
+			return false
+		}
+	}
+	return true
+}
+
+// share returns the fair share of slots of the queue, given the weights of
+// the queues with running or waiting jobs.
+// The caller must hold the pool mutex.
+func (p *slotPool) share(s *queueSlots) float64 {
+	total := 0
+	for _, o := range p.queues {
+		if o == s || o.active > 0 || o.waiting > 0 {
+			total += p.weight(o.name)
+		}
+	}
+	return float64(p.limit*p.weight(s.name)) / float64(total)
+}
+
+// weight returns the weight of the named queue.
+// The caller must hold the pool mutex.
+func (p *slotPool) weight(name string) int {
+	if w, ok := p.weights[name]; ok {
+		return w
+	}
+	return 1
+}
+
+// release returns a slot taken by a job of the queue.
+func (p *slotPool) release(q *Queue) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if s, ok := p.queues[q]; ok && s.active > 0 {
+		s.active--
+	}
+}
+
+// forget removes a queue from the pool, e.g. when it has been replaced.
+func (p *slotPool) forget(q *Queue) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.queues, q)
+}
@@ -0,0 +1,146 @@
+package schedule
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSlotPool_Weights(t *testing.T) {
+	s := NewScheduler()
+	s.MaxConcurrent(4)
+	s.Weight("sync", 3)
+	reporting, syncing := NewQueue(), NewQueue()
+	s.slots.wait(reporting, "reports", 10)
+	s.slots.wait(syncing, "sync", 10)
+	for i, test := range []struct {
+		queue    *Queue
+		name     string
+		expected bool
+	}{
+		{reporting, "reports", true},
+		{reporting, "reports", false},
+		{syncing, "sync", true},
+		{syncing, "sync", true},
+		{syncing, "sync", true},
+		{syncing, "sync", false},
+		{reporting, "reports", false},
+	} {
+		if actual := s.slots.acquire(test.queue, test.name); actual != test.expected {
+			t.Errorf("Acquire %d for %s did not match. Got %v, expected %v", i, test.name, actual, test.expected)
+		}
+	}
+	s.slots.release(syncing)
+	if !s.slots.acquire(syncing, "sync") {
+		t.Error("Expected a released slot to be available again")
+	}
+}
+
+func TestSlotPool_WorkConserving(t *testing.T) {
+	s := NewScheduler()
+	s.MaxConcurrent(4)
+	s.Weight("sync", 3)
+	reporting, syncing := NewQueue(), NewQueue()
+	s.slots.wait(reporting, "reports", 10)
+	s.slots.wait(syncing, "sync", 0)
+	for i := 0; i < 4; i++ {
+		if !s.slots.acquire(reporting, "reports") {
+			t.Fatalf("Expected slot %d to be available to the only busy queue", i)
+		}
+	}
+	if s.slots.acquire(reporting, "reports") {
+		t.Error("Expected the scheduler limit to apply")
+	}
+}
+
+func TestSlotPool_MinSlots(t *testing.T) {
+	s := NewScheduler()
+	s.MaxConcurrent(4)
+	s.Weight("sync", 3)
+	s.MinSlots("reports", 2)
+	reporting, syncing := NewQueue(), NewQueue()
+	s.slots.wait(reporting, "reports", 10)
+	s.slots.wait(syncing, "sync", 10)
+	for i, test := range []struct {
+		queue    *Queue
+		name     string
+		expected bool
+	}{
+		{syncing, "sync", true},
+		{syncing, "sync", true},
+		{syncing, "sync", false},
+		{reporting, "reports", true},
+		{reporting, "reports", true},
+		{reporting, "reports", false},
+	} {
+		if actual := s.slots.acquire(test.queue, test.name); actual != test.expected {
+			t.Errorf("Acquire %d for %s did not match. Got %v, expected %v", i, test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestSlotPool_Unlimited(t *testing.T) {
+	s := NewScheduler()
+	q := NewQueue()
+	for i := 0; i < 100; i++ {
+		if !s.slots.acquire(q, "test") {
+			t.Fatal("Expected slots to be unlimited by default")
+		}
+	}
+	var p *slotPool
+	if !p.acquire(q, "test") {
+		t.Error("Expected queues without a scheduler to be unlimited")
+	}
+}
+
+func TestScheduler_MaxConcurrent(t *testing.T) {
+	s := NewScheduler()
+	s.Queue("reports", NewQueue())
+	s.Queue("sync", NewQueue())
+	s.MaxConcurrent(3)
+	s.Weight("sync", 2)
+	var mutex sync.Mutex
+	running, peak, runs := 0, 0, 0
+	for _, name := range []string{"reports", "sync"} {
+		for i := 0; i < 4; i++ {
+			j, err := NewJob("test", func() {
+				mutex.Lock()
+				running++
+				runs++
+				if running > peak {
+					peak = running
+				}
+				mutex.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+			})
+			if err != nil {
+				t.Fatalf("Could not create test Job: %v", err)
+			}
+			s.AddToQueue(name, j)
+			j.Trigger()
+		}
+	}
+	for i := 0; i < 50; i++ {
+		s.Queues["reports"].Run()
+		s.Queues["sync"].Run()
+		time.Sleep(5 * time.Millisecond)
+		mutex.Lock()
+		n := runs
+		mutex.Unlock()
+		if n == 8 {
+			break
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	mutex.Lock()
+	defer mutex.Unlock()
+	if runs != 8 {
+		t.Errorf("Number of runs did not match. Got %d, expected %d", runs, 8)
+	}
+	if peak > 3 {
+		t.Errorf("Peak concurrency exceeded the scheduler limit. Got %d, expected at most %d", peak, 3)
+	}
+}
@@ -0,0 +1,138 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// A RunOutcome represents the outcome of a single job run.
+type RunOutcome int
+
+const (
+	// OutcomeSuccess indicates that the job function returned normally.
+	OutcomeSuccess RunOutcome = iota
+	// OutcomeFailure indicates that the job function panicked or could not
+	// be called with the provided arguments.
+	OutcomeFailure
+)
+
+// String returns the name of the outcome.
+func (o RunOutcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeFailure:
+		return "failure"
+	}
+	return "unknown"
+}
+
+// A RunRecord represents a single run of a job.
+// Scheduled holds the time the run was due, while Start and End hold the
+// actual time the job function was called and returned.
+type RunRecord struct {
+	ID        int64
+	Scheduled time.Time
+	Start     time.Time
+	End       time.Time
+	Outcome   RunOutcome
+	Error     error
+	Attempt   int
+}
+
+// Duration returns the time it took for the job function to return.
+func (r RunRecord) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// JobStats represents aggregated statistics for a job.
+// The run counters cover every run of the job, while the durations are
+// calculated from the run history retained by the job.
+type JobStats struct {
+	Runs                int64
+	Successes           int64
+	Failures            int64
+	ConsecutiveFailures int64
+	MinDuration         time.Duration
+	AvgDuration         time.Duration
+	MaxDuration         time.Duration
+	P95Duration         time.Duration
+}
+
+// A HistoryStore represents persistent storage for job run records.
+type HistoryStore interface {
+	// LoadRuns returns the stored records for the named job, oldest first.
+	LoadRuns(job string) ([]RunRecord, error)
+	// SaveRun stores a single record for the named job.
+	SaveRun(job string, record RunRecord) error
+}
+
+// runHistory is a bounded ring of run records.
+type runHistory struct {
+	records []RunRecord
+	head    int
+	size    int
+}
+
+// newRunHistory creates a new runHistory holding at most n records.
+func newRunHistory(n int) *runHistory {
+	if n < 0 {
+		n = 0
+	}
+	return &runHistory{
+		records: make([]RunRecord, n),
+	}
+}
+
+// add appends a record, overwriting the oldest record if the ring is full.
+func (h *runHistory) add(record RunRecord) {
+	if len(h.records) == 0 {
+		return
+	}
+	h.records[(h.head+h.size)%len(h.records)] = record
+	if h.size < len(h.records) {
+		h.size++
+	} else {
+		h.head = (h.head + 1) % len(h.records)
+	}
+}
+
+// list returns a copy of the records in the ring, oldest first.
+func (h *runHistory) list() []RunRecord {
+	records := make([]RunRecord, h.size)
+	for i := range records {
+		records[i] = h.records[(h.head+i)%len(h.records)]
+	}
+	return records
+}
+
+// resize creates a copy of the ring holding at most n records.
+// If the ring holds more than n records, the oldest ones are dropped.
+func (h *runHistory) resize(n int) *runHistory {
+	history := newRunHistory(n)
+	for _, record := range h.list() {
+		history.add(record)
+	}
+	return history
+}
+
+// durations fills in the duration fields of stats from the records in
+// the ring.
+func (h *runHistory) durations(stats *JobStats) {
+	if h.size == 0 {
+		return
+	}
+	durations := make([]time.Duration, h.size)
+	total := time.Duration(0)
+	for i, record := range h.list() {
+		durations[i] = record.Duration()
+		total += durations[i]
+	}
+	sort.Slice(durations, func(a, b int) bool {
+		return durations[a] < durations[b]
+	})
+	stats.MinDuration = durations[0]
+	stats.MaxDuration = durations[len(durations)-1]
+	stats.AvgDuration = total / time.Duration(len(durations))
+	stats.P95Duration = durations[(len(durations)*95+99)/100-1]
+}
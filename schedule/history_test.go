@@ -0,0 +1,173 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	records map[string][]RunRecord
+	err     error
+}
+
+func (m *memoryStore) LoadRuns(job string) ([]RunRecord, error) {
+	return m.records[job], m.err
+}
+
+func (m *memoryStore) SaveRun(job string, record RunRecord) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.records[job] = append(m.records[job], record)
+	return nil
+}
+
+func TestRunOutcome_String(t *testing.T) {
+	if OutcomeSuccess.String() != "success" {
+		t.Errorf("Outcome name did not match. Got %s, expected success", OutcomeSuccess)
+	}
+	if OutcomeFailure.String() != "failure" {
+		t.Errorf("Outcome name did not match. Got %s, expected failure", OutcomeFailure)
+	}
+}
+
+func TestRunRecord_Duration(t *testing.T) {
+	start := time.Now()
+	r := RunRecord{Start: start, End: start.Add(time.Second)}
+	if r.Duration() != time.Second {
+		t.Errorf("Duration did not match. Got %v, expected %v", r.Duration(), time.Second)
+	}
+}
+
+func TestJob_History(t *testing.T) {
+	fail := false
+	j, err := NewJob("test", func() {
+		if fail {
+			panic("test")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Run()
+	fail = true
+	j.Run()
+	h := j.History()
+	if len(h) != 2 {
+		t.Fatalf("Number of records did not match. Got %d, expected 2", len(h))
+	}
+	if h[0].ID != 1 || h[0].Outcome != OutcomeSuccess || h[0].Error != nil {
+		t.Errorf("First record did not match. Got %+v", h[0])
+	}
+	if h[1].ID != 2 || h[1].Outcome != OutcomeFailure || h[1].Error == nil {
+		t.Errorf("Second record did not match. Got %+v", h[1])
+	}
+	if h[1].Attempt != 1 {
+		t.Errorf("Attempt did not match. Got %d, expected 1", h[1].Attempt)
+	}
+}
+
+func TestJob_MaxHistory(t *testing.T) {
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		j.Run()
+	}
+	j.MaxHistory(3)
+	h := j.History()
+	if len(h) != 3 {
+		t.Fatalf("Number of records did not match. Got %d, expected 3", len(h))
+	}
+	j.Run()
+	h = j.History()
+	if h[0].ID != 4 || h[2].ID != 6 {
+		t.Errorf("Retained records did not match. Got IDs %d to %d, expected 4 to 6", h[0].ID, h[2].ID)
+	}
+}
+
+func TestJob_Persist(t *testing.T) {
+	store := &memoryStore{records: map[string][]RunRecord{
+		"test": {{ID: 7, Outcome: OutcomeFailure, Error: errors.New("test")}},
+	}}
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Persist(store); err != nil {
+		t.Fatal(err)
+	}
+	j.Run()
+	if len(store.records["test"]) != 2 {
+		t.Fatalf("Number of stored records did not match. Got %d, expected 2", len(store.records["test"]))
+	}
+	if id := store.records["test"][1].ID; id != 8 {
+		t.Errorf("Stored record ID did not match. Got %d, expected 8", id)
+	}
+	if s := j.Stats(); s.Runs != 2 || s.Failures != 1 {
+		t.Errorf("Stats did not include stored records. Got %+v", s)
+	}
+}
+
+func TestJob_PersistError(t *testing.T) {
+	store := &memoryStore{err: errors.New("test")}
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Persist(store); err == nil {
+		t.Error("Persist did not fail with a failing store")
+	}
+}
+
+func TestJob_Stats(t *testing.T) {
+	fail := false
+	j, err := NewJob("test", func() {
+		if fail {
+			panic("test")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Run()
+	fail = true
+	j.Run()
+	j.Run()
+	s := j.Stats()
+	if s.Runs != 3 || s.Successes != 1 || s.Failures != 2 {
+		t.Errorf("Run counters did not match. Got %+v", s)
+	}
+	if s.ConsecutiveFailures != 2 {
+		t.Errorf("Consecutive failures did not match. Got %d, expected 2", s.ConsecutiveFailures)
+	}
+	fail = false
+	j.Run()
+	if s = j.Stats(); s.ConsecutiveFailures != 0 {
+		t.Errorf("Consecutive failures did not match. Got %d, expected 0", s.ConsecutiveFailures)
+	}
+}
+
+func TestRunHistory_Durations(t *testing.T) {
+	h := newRunHistory(100)
+	start := time.Now()
+	for i := 1; i <= 100; i++ {
+		h.add(RunRecord{Start: start, End: start.Add(time.Duration(i) * time.Millisecond)})
+	}
+	var s JobStats
+	h.durations(&s)
+	if s.MinDuration != time.Millisecond {
+		t.Errorf("Min duration did not match. Got %v, expected 1ms", s.MinDuration)
+	}
+	if s.MaxDuration != 100*time.Millisecond {
+		t.Errorf("Max duration did not match. Got %v, expected 100ms", s.MaxDuration)
+	}
+	if s.AvgDuration != 50500*time.Microsecond {
+		t.Errorf("Avg duration did not match. Got %v, expected 50.5ms", s.AvgDuration)
+	}
+	if s.P95Duration != 95*time.Millisecond {
+		t.Errorf("P95 duration did not match. Got %v, expected 95ms", s.P95Duration)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 )
 
@@ -22,13 +23,52 @@ type JobError struct {
 	Error error
 }
 
+// errorType is the reflected type of the error interface.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // A Job represents an executable job.
 type Job struct {
-	Name     string
-	function reflect.Value
-	args     []reflect.Value
-	trigger  *Trigger
-	last     time.Time
+	Name        string
+	function    reflect.Value
+	args        []reflect.Value
+	trigger     *Trigger
+	last        time.Time
+	mutex       sync.RWMutex
+	history     *runHistory
+	runID       int64
+	runs        int64
+	successes   int64
+	failures    int64
+	consecutive int64
+	store       HistoryStore
+	state       JobState
+	running     int
+	since       time.Time
+	paused      bool
+	disabled    bool
+	queues      []*Queue
+	attempts    int64
+	succeeded   int64
+	started     time.Time
+	finished    time.Time
+	pending     []runRequest
+	then        *Job
+	failed      *Job
+	priority    int
+	limiters    []*RateLimiter
+	labels      map[string]string
+	strict      bool
+}
+
+// maxPendingRuns is the number of requested runs a job holds before the
+// oldest requests are dropped. See Job.TriggerWith.
+const maxPendingRuns = 100
+
+// runRequest represents a run of a job requested by Job.Trigger or
+// Job.TriggerWith. If args is nil, the job is called with its own arguments.
+type runRequest struct {
+	requested time.Time
+	args      []reflect.Value
 }
 
 // NewJob creates a new Job for the given function.
@@ -48,6 +88,7 @@ func NewJob(name string, fun interface{}, args ...interface{}) (*Job, error) {
 		function: function,
 		args:     arguments,
 		trigger:  NewTrigger(),
+		history:  newRunHistory(100),
 	}, nil
 }
 
@@ -60,26 +101,185 @@ func (j *Job) Args() []interface{} {
 	return args
 }
 
+// Disable disables the job. A disabled job will not be run by any queue
+// until it is enabled again.
+func (j *Job) Disable() {
+	j.transition(func() {
+		j.disabled = true
+	})
+}
+
+// Enable enables a disabled job.
+func (j *Job) Enable() {
+	j.transition(func() {
+		j.disabled = false
+	})
+}
+
+// History returns the run records retained by the job, oldest first.
+// By default, the last 100 runs are retained. See MaxHistory.
+func (j *Job) History() []RunRecord {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return j.history.list()
+}
+
 // LastRun returns the timestamp of the last successful run.
 // Note that if the job errors, this timestamp will not be updated.
 func (j *Job) LastRun() time.Time {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
 	return j.last
 }
 
+// MaxHistory sets the number of run records retained by the job.
+// If the job has more records than n, the oldest ones are dropped.
+func (j *Job) MaxHistory(n int) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.history = j.history.resize(n)
+}
+
 // NextRun returns the timestamp of the next scheduled run.
+// The next run is the first scheduled time after the start of the last run,
+// or after the end of the last run for fixed delay triggers, so if the job is
+// due, the returned time may be in the past.
+// If the job has reached its limit or its trigger has no more scheduled
+// times, a zeroed time.Time is returned.
 func (j *Job) NextRun() time.Time {
-	next := j.trigger.Next()
-	if j.last.Before(next) {
-		return next
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	if j.exhausted() {
+		return time.Time{}
+	}
+	return j.trigger.nextRun(j.started, j.finished)
+}
+
+// Pause pauses the job. A paused job will not be run by any queue until it
+// is resumed. If the job is currently running, the run is not interrupted.
+func (j *Job) Pause() {
+	j.transition(func() {
+		j.paused = true
+	})
+}
+
+// Persist attaches a HistoryStore to the job.
+// The records already present in the store are loaded into the job history
+// and statistics, and every following run is saved to the store.
+func (j *Job) Persist(store HistoryStore) error {
+	records, err := store.LoadRuns(j.Name)
+	if err != nil {
+		return fmt.Errorf("schedule: could not load run history: %v", err)
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	for _, record := range records {
+		j.count(record)
+		if record.ID > j.runID {
+			j.runID = record.ID
+		}
+	}
+	j.store = store
+	return nil
+}
+
+// Remaining returns the number of runs left before the job reaches the limit
+// of its trigger. If the trigger has no limit, -1 is returned.
+func (j *Job) Remaining() int64 {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	if j.trigger.limit == 0 {
+		return -1
+	}
+	if n := j.trigger.limit - j.counted(); n > 0 {
+		return n
 	}
-	return time.Time{}
+	return 0
+}
+
+// Resume resumes a paused job.
+func (j *Job) Resume() {
+	j.transition(func() {
+		j.paused = false
+	})
 }
 
 // Run attempts to call the job function with the provided arguments.
 // If the arguments do not match the function, an error is returned.
 // This function will also recover from any panic caused inside a job and
-// return the panic value as an error.
-func (j *Job) Run() (result []interface{}, err error) {
+// return the panic value as an error. If FailOnError is set and the last
+// return value of the function is a non-nil error, the run fails and the
+// error is returned along with the results.
+func (j *Job) Run() ([]interface{}, error) {
+	result, _, err := j.run(time.Now(), j.args)
+	return result, err
+}
+
+// run calls the job function with args and records the run in the job
+// history, returning the record along with the results. If the record could
+// not be saved to the attached HistoryStore, the error is returned unless
+// the job itself failed, while the record holds the outcome of the run.
+func (j *Job) run(scheduled time.Time, args []reflect.Value) ([]interface{}, RunRecord, error) {
+	start := time.Now()
+	j.transition(func() {
+		j.running++
+		if j.running == 1 {
+			j.since = start
+		}
+	})
+	result, err := j.call(args)
+	if err == nil {
+		err = j.failure(result)
+	}
+	record := RunRecord{
+		Scheduled: scheduled,
+		Start:     start,
+		End:       time.Now(),
+		Outcome:   OutcomeSuccess,
+		Error:     err,
+		Attempt:   1,
+	}
+	if err != nil {
+		record.Outcome = OutcomeFailure
+	}
+	var store HistoryStore
+	j.transition(func() {
+		j.runID++
+		record.ID = j.runID
+		j.count(record)
+		store = j.store
+		j.running--
+		if j.running == 0 {
+			j.since = time.Time{}
+		}
+		j.attempts++
+		if start.After(j.started) {
+			j.started = start
+		}
+		if record.End.After(j.finished) {
+			j.finished = record.End
+		}
+		if err != nil {
+			j.state = StateFailed
+		} else {
+			j.succeeded++
+			j.state = StateScheduled
+		}
+		if j.exhausted() {
+			j.state = StateCompleted
+		}
+	})
+	if store != nil {
+		if serr := store.SaveRun(j.Name, record); serr != nil && err == nil {
+			err = fmt.Errorf("schedule: could not save run record: %v", serr)
+		}
+	}
+	return result, record, err
+}
+
+// call calls the job function with args, recovering from any panic inside
+// it.
+func (j *Job) call(args []reflect.Value) (result []interface{}, err error) {
 	defer func() {
 		if e := recover(); e != nil {
 			switch e.(type) {
@@ -88,19 +288,312 @@ func (j *Job) Run() (result []interface{}, err error) {
 			default:
 				err = fmt.Errorf("schedule: job panicked with value %#q", e)
 			}
-		} else {
-			j.last = time.Now()
 		}
 	}()
-	for _, res := range j.function.Call(j.args) {
+	for _, res := range j.function.Call(args) {
 		result = append(result, res.Interface())
 	}
 	return
 }
 
+// FailOnError sets the job to fail any run where the last return value of
+// its function is a non-nil error, as if the function had panicked.
+// Such runs are recorded as failures, move the job to StateFailed, are not
+// counted by Trigger.LimitSuccessful and emit the error to the Errors
+// channel of the queue running the job, and the failure job set by
+// OnFailure is run.
+// By default, an error returned by the function is a result like any other.
+// Jobs added to a Workflow and jobs created by NewMapJob fail on errors.
+func (j *Job) FailOnError() *Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.strict = true
+	return j
+}
+
+// failure returns the error returned by the job function as its last return
+// value, if FailOnError is set.
+func (j *Job) failure(result []interface{}) error {
+	j.mutex.RLock()
+	strict := j.strict
+	j.mutex.RUnlock()
+	t := j.function.Type()
+	if !strict || t.NumOut() == 0 || t.Out(t.NumOut()-1) != errorType || len(result) != t.NumOut() {
+		return nil
+	}
+	err, _ := result[len(result)-1].(error)
+	return err
+}
+
+// count adds a record to the job history and updates the run counters.
+// The caller must hold the job mutex.
+func (j *Job) count(record RunRecord) {
+	j.history.add(record)
+	j.runs++
+	if record.Outcome == OutcomeSuccess {
+		j.successes++
+		j.consecutive = 0
+		if record.End.After(j.last) {
+			j.last = record.End
+		}
+	} else {
+		j.failures++
+		j.consecutive++
+	}
+}
+
+// RunCount returns the number of runs counted towards the limit of the job
+// trigger since the job was last scheduled. Depending on the trigger, this is
+// either the number of attempted or successful runs. See Trigger.Limit and
+// Trigger.LimitSuccessful.
+func (j *Job) RunCount() int64 {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return j.counted()
+}
+
+// RunningSince returns the time the job started running.
+// If the job is not currently running, a zeroed time.Time is returned.
+func (j *Job) RunningSince() time.Time {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return j.since
+}
+
+// Trigger requests a run of the job, regardless of its schedule. The run is
+// dispatched by the queues of the job on their next run, like any scheduled
+// run, and counts towards the limit of the job trigger.
+// Requests made before the pending run starts are coalesced into a single
+// run. If the job is running, paused or disabled, the run is dispatched once
+// the job may be run again. A completed job is still run when requested,
+// after which it remains completed.
+func (j *Job) Trigger() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	for _, r := range j.pending {
+		if r.args == nil {
+			return
+		}
+	}
+	j.pending = append(j.pending, runRequest{time.Now(), nil})
+}
+
+// TriggerWith requests a run of the job, calling the job function with the
+// given arguments instead of its own. See Trigger.
+// Unlike Trigger, every request results in a separate run, in the order they
+// were requested. At most 100 requests are kept pending, after which the
+// oldest pending request is dropped for every new one, so that a job which
+// is requested faster than it runs catches up with the latest requests.
+func (j *Job) TriggerWith(args ...interface{}) {
+	arguments := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		arguments[i] = reflect.ValueOf(arg)
+		if !arguments[i].IsValid() {
+			arguments[i] = reflect.Zero(j.in(i))
+		}
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if len(j.pending) >= maxPendingRuns {
+		j.pending = j.pending[len(j.pending)-maxPendingRuns+1:]
+	}
+	j.pending = append(j.pending, runRequest{time.Now(), arguments})
+}
+
+// in returns the type of the ith argument of the job function, taking
+// variadic arguments into account.
+func (j *Job) in(i int) reflect.Type {
+	t := j.function.Type()
+	if t.IsVariadic() && i >= t.NumIn()-1 {
+		return t.In(t.NumIn() - 1).Elem()
+	}
+	if i < t.NumIn() {
+		return t.In(i)
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
+
+// TriggerOn requests a run of the job every time a value is received on ch,
+// until ch is closed. See Trigger.
+func (j *Job) TriggerOn(ch <-chan struct{}) {
+	go func() {
+		for range ch {
+			j.Trigger()
+		}
+	}()
+}
+
 // Schedule creates a new Trigger and returns it so that a schedule may
 // be constructed.
+// The run count of the job is reset, and if the job was completed it will
+// be scheduled again.
 func (j *Job) Schedule() *Trigger {
-	j.trigger = NewTrigger()
-	return j.trigger
+	trigger := NewTrigger()
+	j.transition(func() {
+		j.trigger = trigger
+		j.attempts = 0
+		j.succeeded = 0
+		j.started = time.Time{}
+		j.finished = time.Time{}
+		if j.state == StateCompleted {
+			j.state = StateScheduled
+		}
+	})
+	return trigger
+}
+
+// Stats returns the aggregated run statistics for the job.
+func (j *Job) Stats() JobStats {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	stats := JobStats{
+		Runs:                j.runs,
+		Successes:           j.successes,
+		Failures:            j.failures,
+		ConsecutiveFailures: j.consecutive,
+	}
+	j.history.durations(&stats)
+	return stats
+}
+
+// State returns the current lifecycle state of the job.
+func (j *Job) State() JobState {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return j.currentState()
+}
+
+// currentState returns the current lifecycle state of the job.
+// The caller must hold the job mutex.
+func (j *Job) currentState() JobState {
+	switch {
+	case j.running > 0:
+		return StateRunning
+	case j.disabled:
+		return StateDisabled
+	case j.paused:
+		return StatePaused
+	}
+	return j.state
+}
+
+// complete marks the job as completed if it will not run again.
+func (j *Job) complete() {
+	j.transition(func() {
+		if j.running == 0 && j.exhausted() {
+			j.state = StateCompleted
+		}
+	})
+}
+
+// counted returns the number of runs counted towards the trigger limit.
+// The caller must hold the job mutex.
+func (j *Job) counted() int64 {
+	if j.trigger.successful {
+		return j.succeeded
+	}
+	return j.attempts
+}
+
+// exhausted returns whether the job has reached the limit of its trigger or
+// the trigger has no more scheduled times after the last run.
+// The caller must hold the job mutex.
+func (j *Job) exhausted() bool {
+	if j.trigger.limit > 0 && j.counted() >= j.trigger.limit {
+		return true
+	}
+	return j.trigger.scheduled() && j.trigger.nextRun(j.started, j.finished).IsZero()
+}
+
+// observe registers a queue to receive the state transitions of the job.
+func (j *Job) observe(q *Queue) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.queues = append(j.queues, q)
+}
+
+// observed returns whether the job has been added to a queue.
+func (j *Job) observed() bool {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return len(j.queues) > 0
+}
+
+// unobserve stops a queue from receiving the state transitions of the job.
+func (j *Job) unobserve(q *Queue) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	for i, o := range j.queues {
+		if o == q {
+			j.queues = append(j.queues[:i:i], j.queues[i+1:]...)
+			return
+		}
+	}
+}
+
+// requested returns whether a run of the job is pending.
+func (j *Job) requested() bool {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return len(j.pending) > 0
+}
+
+// runnable returns whether the job may be started by a queue. A completed
+// job may only be started to run a pending request.
+func (j *Job) runnable() bool {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	switch j.currentState() {
+	case StateScheduled, StateFailed:
+		return true
+	case StateCompleted:
+		return len(j.pending) > 0
+	}
+	return false
+}
+
+// skip skips the scheduled run of the job due at the given time, without
+// recording a run.
+func (j *Job) skip(now time.Time) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if now.After(j.started) {
+		j.started = now
+	}
+	if now.After(j.finished) {
+		j.finished = now
+	}
+}
+
+// take removes and returns the first pending run of the job. If no run is
+// pending, false is returned.
+func (j *Job) take() (runRequest, bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if len(j.pending) == 0 {
+		return runRequest{}, false
+	}
+	r := j.pending[0]
+	j.pending = j.pending[1:]
+	if r.args == nil {
+		r.args = j.args
+	}
+	return r, true
+}
+
+// transition calls f while holding the job mutex and emits a JobEvent to
+// every observing queue if the state of the job changed.
+func (j *Job) transition(f func()) {
+	j.mutex.Lock()
+	from := j.currentState()
+	f()
+	to := j.currentState()
+	queues := j.queues
+	j.mutex.Unlock()
+	if from != to {
+		event := JobEvent{Name: j.Name, From: from, To: to, Time: time.Now()}
+		for _, q := range queues {
+			q.emit(event)
+		}
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewJob(t *testing.T) {
@@ -125,3 +126,241 @@ func TestJob_Schedule(t *testing.T) {
 		t.Errorf("Schedule returned a nil Trigger")
 	}
 }
+
+func TestJob_Disable(t *testing.T) {
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Disable()
+	if j.State() != StateDisabled {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateDisabled)
+	}
+	j.Enable()
+	if j.State() != StateScheduled {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateScheduled)
+	}
+}
+
+func TestJob_Pause(t *testing.T) {
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Pause()
+	if j.State() != StatePaused {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StatePaused)
+	}
+	j.Resume()
+	if j.State() != StateScheduled {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateScheduled)
+	}
+}
+
+func TestJob_RunningSince(t *testing.T) {
+	started := make(chan bool)
+	done := make(chan bool)
+	j, err := NewJob("test", func() {
+		started <- true
+		<-done
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go j.Run()
+	<-started
+	if j.RunningSince().IsZero() {
+		t.Error("RunningSince returned a zeroed time value while running")
+	}
+	if j.State() != StateRunning {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateRunning)
+	}
+	done <- true
+	for j.State() == StateRunning {
+		time.Sleep(time.Millisecond)
+	}
+	if !j.RunningSince().IsZero() {
+		t.Error("RunningSince did not return a zeroed time value after run")
+	}
+}
+
+func TestJob_StateFailed(t *testing.T) {
+	j, err := NewJob("test", func() { panic("test") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Run()
+	if j.State() != StateFailed {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateFailed)
+	}
+}
+
+func TestJob_NextRunLimit(t *testing.T) {
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Schedule().Every("1ms").From(time.Now().AddDate(0, -1, 0)).Limit(1)
+	if j.NextRun().After(time.Now()) {
+		t.Error("NextRun was not due for a job that never ran")
+	}
+	j.Run()
+	if !j.NextRun().IsZero() {
+		t.Errorf("NextRun did not return a zeroed time value. Got %v", j.NextRun())
+	}
+	if j.State() != StateCompleted {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateCompleted)
+	}
+	j.Schedule().Every("1ms")
+	if j.State() != StateScheduled {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateScheduled)
+	}
+}
+
+func TestJob_RunCount(t *testing.T) {
+	fail := true
+	j, err := NewJob("test", func() {
+		if fail {
+			panic("test")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Schedule().Every("1ms").LimitSuccessful(2)
+	j.Run()
+	if j.RunCount() != 0 {
+		t.Errorf("Run count did not match. Got %d, expected 0", j.RunCount())
+	}
+	fail = false
+	j.Run()
+	if j.RunCount() != 1 {
+		t.Errorf("Run count did not match. Got %d, expected 1", j.RunCount())
+	}
+	if j.Remaining() != 1 {
+		t.Errorf("Remaining runs did not match. Got %d, expected 1", j.Remaining())
+	}
+	j.Run()
+	if j.Remaining() != 0 || j.State() != StateCompleted {
+		t.Errorf("Job was not completed. Remaining %d, state %s", j.Remaining(), j.State())
+	}
+}
+
+func TestJob_RemainingUnlimited(t *testing.T) {
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Remaining() != -1 {
+		t.Errorf("Remaining runs did not match. Got %d, expected -1", j.Remaining())
+	}
+}
+
+func TestJob_RunCompleted(t *testing.T) {
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Schedule().At(time.Now().Add(-time.Millisecond))
+	if j.NextRun().IsZero() {
+		t.Fatal("NextRun returned a zeroed time value before the run")
+	}
+	j.Run()
+	if !j.NextRun().IsZero() {
+		t.Errorf("NextRun did not return a zeroed time value. Got %v", j.NextRun())
+	}
+	if j.State() != StateCompleted {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateCompleted)
+	}
+}
+
+func TestJob_NextRunFixedDelay(t *testing.T) {
+	j, err := NewJob("test", func() {
+		time.Sleep(20 * time.Millisecond)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Schedule().EveryAfterCompletion("50ms")
+	j.Run()
+	h := j.History()
+	if n := j.NextRun(); !n.Equal(h[0].End.Add(50 * time.Millisecond)) {
+		t.Errorf("NextRun did not match. Got %v, expected %v", n, h[0].End.Add(50*time.Millisecond))
+	}
+}
+
+func TestJob_RunReturnedError(t *testing.T) {
+	j, err := NewJob("test", func() (string, error) { return "partial", errors.New("test") })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	res, err := j.Run()
+	if err != nil {
+		t.Errorf("Job errored on Run: %v", err)
+	}
+	if len(res) != 2 || res[0] != "partial" || res[1].(error).Error() != "test" {
+		t.Errorf("Job results did not match. Got %v", res)
+	}
+	if j.State() != StateScheduled || j.Stats().Failures != 0 {
+		t.Errorf("Expected a returned error not to fail the run by default. Got %v", j.State())
+	}
+}
+
+func TestJob_FailOnError(t *testing.T) {
+	fail := true
+	j, err := NewJob("test", func() (string, error) {
+		if fail {
+			return "partial", errors.New("test")
+		}
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	j.FailOnError().Schedule().LimitSuccessful(1)
+	res, err := j.Run()
+	if err == nil || err.Error() != "test" {
+		t.Errorf("Job error did not match. Got %v, expected test", err)
+	}
+	if len(res) != 2 || res[0] != "partial" {
+		t.Errorf("Job results did not match. Got %v", res)
+	}
+	if j.State() != StateFailed {
+		t.Errorf("Job state did not match. Got %v, expected %v", j.State(), StateFailed)
+	}
+	if stats := j.Stats(); stats.Failures != 1 || stats.Successes != 0 {
+		t.Errorf("Job stats did not match. Got %+v", stats)
+	}
+	if j.Remaining() != 1 {
+		t.Errorf("Remaining runs did not match. Got %d, expected %d", j.Remaining(), 1)
+	}
+	fail = false
+	if _, err := j.Run(); err != nil {
+		t.Errorf("Job errored on Run: %v", err)
+	}
+	if j.State() != StateCompleted {
+		t.Errorf("Job state did not match. Got %v, expected %v", j.State(), StateCompleted)
+	}
+}
+
+func TestQueue_RunFailOnError(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() error { return errors.New("test") })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	q.Add(j.FailOnError())
+	j.Trigger()
+	q.Run()
+	select {
+	case e := <-q.Errors():
+		if e.Name != "test" || e.Error.Error() != "test" {
+			t.Errorf("Job error did not match. Got %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the returned error to be emitted")
+	}
+	if len(q.Results()) != 1 {
+		t.Errorf("Number of results did not match. Got %d, expected %d", len(q.Results()), 1)
+	}
+}
@@ -0,0 +1,97 @@
+package schedule
+
+// Tags sets labels on the job, e.g. Job.Tags(map[string]string{"env":
+// "prod", "team": "billing"}), so that groups of jobs may be selected by a
+// Scheduler. Labels already set on the job are kept unless overwritten.
+// See ParseSelector.
+func (j *Job) Tags(labels map[string]string) *Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.labels == nil {
+		j.labels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		j.labels[k] = v
+	}
+	return j
+}
+
+// Labels returns a copy of the labels set on the job.
+func (j *Job) Labels() map[string]string {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	labels := make(map[string]string, len(j.labels))
+	for k, v := range j.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// Select returns the jobs in the queues of this Scheduler matching the
+// label selector, e.g. Scheduler.Select("env=prod,team in (billing,ops)").
+// A job present in several queues is returned once. See ParseSelector.
+func (s *Scheduler) Select(selector string) ([]*Job, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[*Job]bool)
+	var jobs []*Job
+	for _, job := range s.jobs() {
+		if !seen[job] && sel.Matches(job.Labels()) {
+			seen[job] = true
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// PauseMatching pauses every job matching the label selector, returning the
+// number of jobs paused. See Select and Job.Pause.
+func (s *Scheduler) PauseMatching(selector string) (int, error) {
+	return s.each(selector, (*Job).Pause)
+}
+
+// ResumeMatching resumes every job matching the label selector, returning
+// the number of jobs resumed. See Select and Job.Resume.
+func (s *Scheduler) ResumeMatching(selector string) (int, error) {
+	return s.each(selector, (*Job).Resume)
+}
+
+// RunMatching requests a run of every job matching the label selector,
+// regardless of their schedules, returning the number of jobs triggered.
+// See Select and Job.Trigger.
+func (s *Scheduler) RunMatching(selector string) (int, error) {
+	return s.each(selector, (*Job).Trigger)
+}
+
+// RemoveMatching removes every job matching the label selector from the
+// queues of this Scheduler, returning the number of jobs removed. Running
+// jobs are not interrupted. See Select and Queue.Remove.
+func (s *Scheduler) RemoveMatching(selector string) (int, error) {
+	jobs, err := s.Select(selector)
+	if err != nil {
+		return 0, err
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, queue := range s.Queues {
+		for _, job := range jobs {
+			queue.Remove(job)
+		}
+	}
+	return len(jobs), nil
+}
+
+// each calls f for every job matching the label selector, returning the
+// number of jobs.
+func (s *Scheduler) each(selector string, f func(*Job)) (int, error) {
+	jobs, err := s.Select(selector)
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range jobs {
+		f(job)
+	}
+	return len(jobs), nil
+}
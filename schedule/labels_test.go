@@ -0,0 +1,124 @@
+package schedule
+
+import "testing"
+
+func TestJob_Tags(t *testing.T) {
+	j, err := NewJob("test", func() {})
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	if len(j.Labels()) != 0 {
+		t.Errorf("Expected no labels by default. Got %v", j.Labels())
+	}
+	j.Tags(map[string]string{"env": "prod", "team": "ops"}).Tags(map[string]string{"team": "billing"})
+	labels := j.Labels()
+	if len(labels) != 2 || labels["env"] != "prod" || labels["team"] != "billing" {
+		t.Errorf("Labels did not match. Got %v", labels)
+	}
+	labels["env"] = "dev"
+	if j.Labels()["env"] != "prod" {
+		t.Error("Expected Labels to return a copy")
+	}
+}
+
+// newTaggedScheduler creates a Scheduler with jobs labelled by env and team.
+func newTaggedScheduler(t *testing.T) (*Scheduler, map[string]*Job) {
+	s := NewScheduler()
+	s.Queue("other", NewQueue())
+	jobs := make(map[string]*Job)
+	for _, test := range []struct {
+		name, env, team string
+	}{
+		{"invoices", "prod", "billing"},
+		{"cleanup", "prod", "ops"},
+		{"search", "prod", "search"},
+		{"staging", "dev", "billing"},
+	} {
+		j, err := NewJob(test.name, func() {})
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		j.Tags(map[string]string{"env": test.env, "team": test.team})
+		jobs[test.name] = j
+		s.Add(j)
+	}
+	s.AddToQueue("other", jobs["cleanup"])
+	return s, jobs
+}
+
+func TestScheduler_Select(t *testing.T) {
+	s, _ := newTaggedScheduler(t)
+	for _, test := range []struct {
+		selector string
+		expected int
+	}{
+		{"", 4},
+		{"env=prod", 3},
+		{"env=prod,team in (billing,ops)", 2},
+		{"team notin (billing)", 2},
+		{"region", 0},
+	} {
+		jobs, err := s.Select(test.selector)
+		if err != nil {
+			t.Errorf("Could not select %q: %v", test.selector, err)
+			continue
+		}
+		if len(jobs) != test.expected {
+			t.Errorf("Number of jobs for %q did not match. Got %d, expected %d", test.selector, len(jobs), test.expected)
+		}
+	}
+	if _, err := s.Select("env in"); err == nil {
+		t.Error("Expected an invalid selector to return an error")
+	}
+}
+
+func TestScheduler_PauseResumeMatching(t *testing.T) {
+	s, jobs := newTaggedScheduler(t)
+	n, err := s.PauseMatching("env=prod,team in (billing,ops)")
+	if err != nil || n != 2 {
+		t.Fatalf("Pause did not match. Got %d, %v, expected %d", n, err, 2)
+	}
+	for name, expected := range map[string]JobState{
+		"invoices": StatePaused,
+		"cleanup":  StatePaused,
+		"search":   StateScheduled,
+		"staging":  StateScheduled,
+	} {
+		if state := jobs[name].State(); state != expected {
+			t.Errorf("State of %s did not match. Got %v, expected %v", name, state, expected)
+		}
+	}
+	if n, err := s.ResumeMatching("team=ops"); err != nil || n != 1 {
+		t.Errorf("Resume did not match. Got %d, %v, expected %d", n, err, 1)
+	}
+	if state := jobs["cleanup"].State(); state != StateScheduled {
+		t.Errorf("State of cleanup did not match. Got %v, expected %v", state, StateScheduled)
+	}
+}
+
+func TestScheduler_RunMatching(t *testing.T) {
+	s, jobs := newTaggedScheduler(t)
+	if n, err := s.RunMatching("env=dev"); err != nil || n != 1 {
+		t.Fatalf("Run did not match. Got %d, %v, expected %d", n, err, 1)
+	}
+	if !jobs["staging"].requested() || jobs["invoices"].requested() {
+		t.Error("Expected only the selected job to be triggered")
+	}
+}
+
+func TestScheduler_RemoveMatching(t *testing.T) {
+	s, jobs := newTaggedScheduler(t)
+	if n, err := s.RemoveMatching("team=ops"); err != nil || n != 1 {
+		t.Fatalf("Remove did not match. Got %d, %v, expected %d", n, err, 1)
+	}
+	for name, queue := range s.Queues {
+		for _, j := range queue.Jobs {
+			if j == jobs["cleanup"] {
+				t.Errorf("Expected cleanup to be removed from queue %s", name)
+			}
+		}
+	}
+	if len(s.Queues["default"].Jobs) != 3 || jobs["cleanup"].observed() {
+		t.Error("Expected only cleanup to be removed")
+	}
+}
@@ -0,0 +1,146 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// A MapItem represents the outcome of the worker function of a map job for
+// a single input. The input is stored in .Input, the return values of the
+// worker in .Results and the error of the worker, if any, in .Error.
+// If the last return value of the worker is an error, it is stored in .Error
+// rather than .Results.
+type MapItem struct {
+	Input   interface{}
+	Results []interface{}
+	Error   error
+}
+
+// A MapResult represents the aggregated outcome of a run of a map job.
+// The items are stored in the order of the inputs returned by the producer.
+type MapResult struct {
+	Items  []MapItem
+	Failed int
+}
+
+// A MapError represents the failure of one or more items of a map job.
+// The total number of items is stored in .Total and the failed items in
+// .Failed.
+type MapError struct {
+	Total  int
+	Failed []MapItem
+}
+
+// Error returns the error message.
+func (e *MapError) Error() string {
+	failures := make([]string, len(e.Failed))
+	for i, item := range e.Failed {
+		failures[i] = fmt.Sprintf("%v: %v", item.Input, item.Error)
+	}
+	return fmt.Sprintf("schedule: %d of %d items failed: %s", len(e.Failed), e.Total, strings.Join(failures, "; "))
+}
+
+// NewMapJob creates a new Job fanning out over a dynamic set of inputs.
+// The producer function is called first, and needs to return a slice of
+// inputs, optionally followed by an error. The worker function is then
+// called once for every input, with up to parallelism calls running at the
+// same time, e.g.
+//
+//	NewMapJob("sync", func() []string { ... }, func(tenant string) error { ... }, 4)
+//
+// The job returns a *MapResult holding the outcome of every item, followed
+// by a *MapError if any item failed, so partial failures fail the run while
+// the results of the other items are still emitted. A panic inside the
+// worker fails only the item it occurred for.
+// If 0 or a negative parallelism is provided, it will be set to 1.
+func NewMapJob(name string, producer, worker interface{}, parallelism int) (*Job, error) {
+	produce, work := reflect.ValueOf(producer), reflect.ValueOf(worker)
+	if produce.Kind() != reflect.Func || work.Kind() != reflect.Func {
+		return nil, errors.New("schedule: jobs can only be created for functions")
+	}
+	pt, wt := produce.Type(), work.Type()
+	if pt.NumIn() != 0 || pt.NumOut() == 0 || pt.NumOut() > 2 || pt.Out(0).Kind() != reflect.Slice ||
+		(pt.NumOut() == 2 && pt.Out(1) != errorType) {
+		return nil, errors.New("schedule: map job producers need to return a slice and optionally an error")
+	}
+	if !accepts(wt, []reflect.Type{pt.Out(0).Elem()}) {
+		return nil, fmt.Errorf("schedule: map job workers need to accept a single %v", pt.Out(0).Elem())
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	m := &mapJob{produce, work, parallelism}
+	job, err := NewJob(name, m.run)
+	if err != nil {
+		return nil, err
+	}
+	return job.FailOnError(), nil
+}
+
+// mapJob is the function of a job created by NewMapJob.
+type mapJob struct {
+	producer    reflect.Value
+	worker      reflect.Value
+	parallelism int
+}
+
+// run calls the producer and fans out over its inputs.
+func (m *mapJob) run() (*MapResult, error) {
+	out := m.producer.Call(nil)
+	if len(out) == 2 && !out[1].IsNil() {
+		return &MapResult{}, out[1].Interface().(error)
+	}
+	inputs := out[0]
+	result := &MapResult{Items: make([]MapItem, inputs.Len())}
+	sem := make(chan struct{}, m.parallelism)
+	var wg sync.WaitGroup
+	for i := 0; i < inputs.Len(); i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, input reflect.Value) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			result.Items[i] = m.call(input)
+		}(i, inputs.Index(i))
+	}
+	wg.Wait()
+	merr := &MapError{Total: len(result.Items)}
+	for _, item := range result.Items {
+		if item.Error != nil {
+			merr.Failed = append(merr.Failed, item)
+		}
+	}
+	result.Failed = len(merr.Failed)
+	if result.Failed > 0 {
+		return result, merr
+	}
+	return result, nil
+}
+
+// call calls the worker for a single input, recovering from any panic
+// inside it.
+func (m *mapJob) call(input reflect.Value) (item MapItem) {
+	item.Input = input.Interface()
+	defer func() {
+		if e := recover(); e != nil {
+			item.Error = fmt.Errorf("schedule: worker panicked with value %#q", e)
+		}
+	}()
+	out := m.worker.Call([]reflect.Value{input})
+	t := m.worker.Type()
+	if len(out) > 0 && t.Out(len(out)-1) == errorType {
+		if err := out[len(out)-1]; !err.IsNil() {
+			item.Error = err.Interface().(error)
+		}
+		out = out[:len(out)-1]
+	}
+	for _, v := range out {
+		item.Results = append(item.Results, v.Interface())
+	}
+	return item
+}
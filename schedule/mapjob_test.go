@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewMapJob(t *testing.T) {
+	var running, peak int32
+	j, err := NewMapJob("sync", func() []string {
+		return []string{"a", "b", "c", "d", "e"}
+	}, func(tenant string) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if tenant == "c" {
+			return "", errors.New("unavailable")
+		}
+		if tenant == "d" {
+			panic("test")
+		}
+		return "synced " + tenant, nil
+	}, 2)
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	res, err := j.Run()
+	var merr *MapError
+	if !errors.As(err, &merr) {
+		t.Fatalf("Job error was not a *MapError. Got %v", err)
+	}
+	if merr.Total != 5 || len(merr.Failed) != 2 || merr.Failed[0].Input != "c" || merr.Failed[1].Input != "d" {
+		t.Errorf("Map error did not match. Got %v", merr)
+	}
+	result := res[0].(*MapResult)
+	if result.Failed != 2 || len(result.Items) != 5 {
+		t.Fatalf("Map result did not match. Got %+v", result)
+	}
+	if item := result.Items[4]; item.Input != "e" || item.Results[0] != "synced e" || item.Error != nil {
+		t.Errorf("Map item did not match. Got %+v", item)
+	}
+	if peak != 2 {
+		t.Errorf("Parallelism did not match. Got %d, expected 2", peak)
+	}
+	if j.State() != StateFailed {
+		t.Errorf("Job state did not match. Got %v, expected %v", j.State(), StateFailed)
+	}
+}
+
+func TestNewMapJobProducerError(t *testing.T) {
+	j, err := NewMapJob("sync", func() ([]int, error) {
+		return nil, errors.New("test")
+	}, func(int) {}, 0)
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	if _, err := j.Run(); err == nil || err.Error() != "test" {
+		t.Errorf("Job error did not match. Got %v, expected test", err)
+	}
+}
+
+func TestNewMapJobError(t *testing.T) {
+	tests := []struct {
+		producer interface{}
+		worker   interface{}
+	}{
+		{"test", func(int) {}},
+		{func() []int { return nil }, nil},
+		{func() int { return 0 }, func(int) {}},
+		{func() ([]int, string) { return nil, "" }, func(int) {}},
+		{func() []int { return nil }, func(string) {}},
+		{func() []int { return nil }, func(int, int) {}},
+	}
+	for _, test := range tests {
+		if _, err := NewMapJob("test", test.producer, test.worker, 1); err == nil {
+			t.Errorf("NewMapJob did not error for %T and %T", test.producer, test.worker)
+		}
+	}
+}
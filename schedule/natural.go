@@ -0,0 +1,577 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var ordinalWords = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+}
+
+var naturalUnits = map[string]string{
+	"second": "second", "seconds": "second", "sec": "second", "secs": "second",
+	"minute": "minute", "minutes": "minute", "min": "minute", "mins": "minute",
+	"hour": "hour", "hours": "hour", "hr": "hour", "hrs": "hour",
+	"day": "day", "days": "day",
+	"week": "week", "weeks": "week",
+	"month": "month", "months": "month",
+}
+
+// naturalParser is a parser for natural language schedule phrases.
+type naturalParser struct {
+	spec    string
+	words   []string
+	offsets []int
+	i       int
+}
+
+// ParseNatural creates a new Trigger from an English schedule phrase, such
+// as:
+//
+//	every 15 minutes
+//	every 2 hours between 9am and 5pm
+//	every 30 minutes on weekdays
+//	every day at 6:30pm
+//	every monday at 9am
+//	every other friday at 17:00
+//	every mon, wed and fri at noon
+//	every 3 months on the last day
+//	on the 1st of every month at noon
+//	on the first monday of the month at 10am
+//	twice a day
+//	4 times an hour
+//	hourly, daily, weekly or monthly
+//
+// Phrases running on days, weeks or months run at midnight unless a time is
+// given. Phrases running every number of seconds, minutes or hours are
+// aligned to the clock rather than to the time the phrase was parsed, e.g.
+// "every 15 minutes" runs at :00, :15, :30 and :45, and count from the
+// opening of a time window if one is given.
+// Times are in the location of the start time of the Trigger.
+// If the phrase is not supported, a *ParseError wrapping
+// ErrUnsupportedPhrase is returned.
+func ParseNatural(phrase string) (*Trigger, error) {
+	p := newNaturalParser(phrase)
+	if len(p.words) == 0 {
+		return nil, &ParseError{phrase, 0, "a schedule", ErrEmptySpec}
+	}
+	t, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// newNaturalParser splits the phrase into lower case words, treating commas
+// as separate words and ignoring a trailing period.
+func newNaturalParser(phrase string) *naturalParser {
+	p := &naturalParser{spec: phrase}
+	trimmed := strings.TrimRight(phrase, ". \t\n")
+	start := -1
+	for i, r := range trimmed + " " {
+		if r == ' ' || r == '\t' || r == '\n' || r == ',' {
+			if start >= 0 {
+				p.words = append(p.words, strings.ToLower(trimmed[start:i]))
+				p.offsets = append(p.offsets, start)
+				start = -1
+			}
+			if r == ',' {
+				p.words = append(p.words, ",")
+				p.offsets = append(p.offsets, i)
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return p
+}
+
+// parse parses the whole phrase.
+func (p *naturalParser) parse() (*Trigger, error) {
+	var t *Trigger
+	var err error
+	switch p.peek() {
+	case "every", "each":
+		p.i++
+		t, err = p.every()
+	case "on":
+		p.i++
+		t, err = p.on()
+	case "hourly":
+		p.i++
+		t = newClockTrigger(time.Hour)
+	case "daily":
+		p.i++
+		t = NewTrigger().Each(1).Day()
+	case "weekly":
+		p.i++
+		t = NewTrigger().Each(1).Week()
+	case "monthly":
+		p.i++
+		t = NewTrigger().Each(1).Month()
+	default:
+		t, err = p.times()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.modifiers(t)
+}
+
+// every parses the remainder of a phrase starting with "every".
+func (p *naturalParser) every() (*Trigger, error) {
+	n := 1
+	if p.accept("other") {
+		n = 2
+	} else if v, ok := p.number(); ok {
+		n = v
+	}
+	word := p.peek()
+	if unit, ok := naturalUnits[word]; ok {
+		p.i++
+		switch unit {
+		case "second":
+			return newClockTrigger(time.Duration(n) * time.Second), nil
+		case "minute":
+			return newClockTrigger(time.Duration(n) * time.Minute), nil
+		case "hour":
+			return newClockTrigger(time.Duration(n) * time.Hour), nil
+		case "day":
+			return NewTrigger().Each(n).Days(), nil
+		case "week":
+			t := NewTrigger().Each(n).Weeks()
+			if p.accept("on") {
+				days, err := p.weekdays()
+				if err != nil {
+					return nil, err
+				}
+				t.On(days...)
+			}
+			return t, nil
+		}
+		t := NewTrigger().Each(n).Months()
+		if p.accept("on") {
+			if !p.accept("the") {
+				return nil, p.fail("\"the\"")
+			}
+			return p.monthDay(t)
+		}
+		return t, nil
+	}
+	days, err := p.weekdays()
+	if err != nil {
+		return nil, p.fail("a unit such as minutes, hours or days, or a weekday")
+	}
+	return NewTrigger().Each(n).Weeks().On(days...), nil
+}
+
+// on parses the remainder of a phrase starting with "on".
+func (p *naturalParser) on() (*Trigger, error) {
+	if !p.accept("the") {
+		days, err := p.weekdays()
+		if err != nil {
+			return nil, err
+		}
+		return NewTrigger().Each(1).Weeks().On(days...), nil
+	}
+	return p.monthDay(NewTrigger().Each(1).Months())
+}
+
+// monthDay parses a day of the month following "the", such as "1st",
+// "last day" or "first monday", optionally followed by the period, such as
+// "of every other month".
+func (p *naturalParser) monthDay(t *Trigger) (*Trigger, error) {
+	switch {
+	case p.accept("last"):
+		if p.accept("day") {
+			t.LastDayOfMonth()
+			break
+		}
+		day, ok := p.weekday()
+		if !ok {
+			return nil, p.fail("\"day\" or a weekday")
+		}
+		t.NthWeekday(-1, day)
+	default:
+		n, ok := p.ordinal()
+		if !ok {
+			return nil, p.fail("an ordinal such as 1st, first or last")
+		}
+		if day, ok := p.weekday(); ok {
+			if n > 5 {
+				p.i -= 2
+				return nil, p.fail("a weekday ordinal from first to fifth")
+			}
+			t.NthWeekday(n, day)
+			break
+		}
+		if n > 31 {
+			p.i--
+			return nil, p.fail("a day of the month from 1st to 31st")
+		}
+		p.accept("day")
+		t.MonthlyOn(n)
+	}
+	if !p.accept("of") {
+		return t, nil
+	}
+	switch {
+	case p.accept("the"):
+	case p.accept("every", "each"):
+		if p.accept("other") {
+			t.calendar().n = 2
+		} else if v, ok := p.number(); ok {
+			t.calendar().n = v
+		}
+	default:
+		return nil, p.fail("\"every\" or \"the\"")
+	}
+	if naturalUnits[p.peek()] != "month" {
+		return nil, p.fail("\"month\"")
+	}
+	p.i++
+	return t, nil
+}
+
+// times parses a phrase such as "twice a day" or "4 times an hour".
+func (p *naturalParser) times() (*Trigger, error) {
+	var n int
+	switch p.peek() {
+	case "once":
+		n = 1
+	case "twice":
+		n = 2
+	case "thrice":
+		n = 3
+	default:
+		v, ok := p.number()
+		if !ok || !p.accept("times") {
+			p.i = 0
+			return nil, p.fail("a phrase starting with every, on, hourly, daily, weekly, monthly, once or twice")
+		}
+		n = v
+		p.i--
+	}
+	p.i++
+	if !p.accept("a", "an", "per", "every", "each") {
+		return nil, p.fail("\"a\" or \"per\"")
+	}
+	switch naturalUnits[p.peek()] {
+	case "day":
+		if 24%n != 0 {
+			return nil, p.fail("a count dividing a day into whole hours")
+		}
+		p.i++
+		if n == 1 {
+			return NewTrigger().Each(1).Day(), nil
+		}
+		hours := make([]string, n)
+		for i := range hours {
+			hours[i] = strconv.Itoa(i * 24 / n)
+		}
+		c, err := parseCron("0 " + strings.Join(hours, ",") + " * * *")
+		if err != nil {
+			return nil, err
+		}
+		return newRuleTrigger(c), nil
+	case "hour":
+		if 60%n != 0 {
+			return nil, p.fail("a count dividing an hour into whole minutes")
+		}
+		p.i++
+		return newClockTrigger(time.Hour / time.Duration(n)), nil
+	case "week":
+		if n != 1 {
+			return nil, p.fail("\"day\" or \"hour\"")
+		}
+		p.i++
+		return NewTrigger().Each(1).Week(), nil
+	}
+	return nil, p.fail("\"day\" or \"hour\"")
+}
+
+// modifiers parses the optional time of day, weekdays and time window
+// following the schedule, and ensures the whole phrase has been parsed.
+func (p *naturalParser) modifiers(t *Trigger) (*Trigger, error) {
+	c, isCalendar := t.rule.(*calendarRule)
+	if isCalendar && p.accept("at") {
+		clock, err := p.clock()
+		if err != nil {
+			return nil, err
+		}
+		c.clock, c.hour, c.minute = true, clock/60, clock%60
+	} else if isCalendar {
+		c.clock = true
+	}
+	interval, isInterval := t.rule.(clockInterval)
+	if isInterval && p.accept("on") {
+		days, err := p.weekdays()
+		if err != nil {
+			return nil, err
+		}
+		cal := NewHolidayCalendar()
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if !containsWeekday(days, d) {
+				cal.ExcludeWeekday(d)
+			}
+		}
+		t.Exclude(cal)
+	}
+	if p.accept("between", "from") {
+		from, err := p.clock()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept("and", "to", "until") {
+			return nil, p.fail("\"and\"")
+		}
+		to, err := p.clock()
+		if err != nil {
+			return nil, err
+		}
+		if isInterval {
+			// Count from the opening of the window, so that it runs on it.
+			interval.offset = time.Duration(from) * time.Minute
+			t.rule = interval
+		}
+		t = Window(t, formatClock(from), formatClock(to))
+	}
+	if p.i < len(p.words) {
+		return nil, p.fail("the end of the phrase")
+	}
+	return t, nil
+}
+
+// weekdays parses a list of weekdays, such as "monday", "mon, wed and fri",
+// "weekdays" or "weekends".
+func (p *naturalParser) weekdays() ([]time.Weekday, error) {
+	switch p.peek() {
+	case "weekday", "weekdays":
+		p.i++
+		return []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, nil
+	case "weekend", "weekends":
+		p.i++
+		return []time.Weekday{time.Saturday, time.Sunday}, nil
+	}
+	var days []time.Weekday
+	for {
+		day, ok := p.weekday()
+		if !ok {
+			return nil, p.fail("a weekday")
+		}
+		days = append(days, day)
+		if p.accept(",") {
+			p.accept("and")
+		} else if !p.accept("and") {
+			return days, nil
+		}
+	}
+}
+
+// weekday parses the name of a weekday, e.g. "monday", "mondays" or "mon".
+func (p *naturalParser) weekday() (time.Weekday, bool) {
+	word := p.peek()
+	day, ok := weekdayNames[word]
+	if !ok && strings.HasSuffix(word, "s") {
+		day, ok = weekdayNames[word[:len(word)-1]]
+	}
+	if ok {
+		p.i++
+	}
+	return day, ok
+}
+
+// number parses a positive number, e.g. "15" or "two".
+func (p *naturalParser) number() (int, bool) {
+	word := p.peek()
+	n, ok := numberWords[word]
+	if !ok {
+		v, err := strconv.Atoi(word)
+		n, ok = v, err == nil && v > 0
+	}
+	if ok {
+		p.i++
+	}
+	return n, ok
+}
+
+// ordinal parses an ordinal number, e.g. "1st", "22nd" or "first".
+func (p *naturalParser) ordinal() (int, bool) {
+	word := p.peek()
+	n, ok := ordinalWords[word]
+	if !ok && len(word) > 2 {
+		v, err := strconv.Atoi(word[:len(word)-2])
+		n, ok = v, err == nil && v > 0 && describeOrdinal(v) == word
+	}
+	if ok {
+		p.i++
+	}
+	return n, ok
+}
+
+// clock parses a time of day, e.g. "9am", "9:30 pm", "17:45", "noon" or
+// "midnight", returning the number of minutes after midnight.
+func (p *naturalParser) clock() (int, error) {
+	word := strings.Replace(p.peek(), ".", "", -1)
+	switch word {
+	case "noon", "midday":
+		p.i++
+		return 12 * 60, nil
+	case "midnight":
+		p.i++
+		return 0, nil
+	}
+	fail := p.fail("a time such as 9am, 6:30pm or 17:45")
+	words, suffix := 1, ""
+	if strings.HasSuffix(word, "am") || strings.HasSuffix(word, "pm") {
+		word, suffix = word[:len(word)-2], word[len(word)-2:]
+	} else if p.i+1 < len(p.words) {
+		next := strings.Replace(p.words[p.i+1], ".", "", -1)
+		if next == "am" || next == "pm" {
+			words, suffix = 2, next
+		}
+	}
+	hour, minute := word, "00"
+	if i := strings.Index(word, ":"); i >= 0 {
+		hour, minute = word[:i], word[i+1:]
+	} else if suffix == "" {
+		return 0, fail
+	}
+	h, err := strconv.Atoi(hour)
+	if err != nil || len(minute) != 2 {
+		return 0, fail
+	}
+	m, err := strconv.Atoi(minute)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fail
+	}
+	switch {
+	case suffix == "" && (h < 0 || h > 23):
+		return 0, fail
+	case suffix != "" && (h < 1 || h > 12):
+		return 0, fail
+	case suffix != "":
+		h %= 12
+		if suffix == "pm" {
+			h += 12
+		}
+	}
+	p.i += words
+	return h*60 + m, nil
+}
+
+// peek returns the current word, or an empty string at the end of the
+// phrase.
+func (p *naturalParser) peek() string {
+	if p.i < len(p.words) {
+		return p.words[p.i]
+	}
+	return ""
+}
+
+// accept consumes the current word if it is one of the given words.
+func (p *naturalParser) accept(words ...string) bool {
+	for _, w := range words {
+		if p.peek() == w {
+			p.i++
+			return true
+		}
+	}
+	return false
+}
+
+// fail returns a *ParseError for the current word.
+func (p *naturalParser) fail(expected string) error {
+	pos := len(p.spec)
+	if p.i < len(p.offsets) {
+		pos = p.offsets[p.i]
+	}
+	return &ParseError{p.spec, pos, expected, ErrUnsupportedPhrase}
+}
+
+// clockInterval is a recurrence running every d, aligned to the clock in
+// the location of the start time of the Trigger rather than counting from
+// the start time itself. If d divides a day, it runs at the times of day
+// offset by a multiple of d, e.g. at 09:00, 11:00 and 13:00 for two hours
+// offset by nine hours. Otherwise, it runs every d from the offset after
+// midnight of the day of the start time.
+type clockInterval struct {
+	d      time.Duration
+	offset time.Duration
+}
+
+// newClockTrigger creates a Trigger running every d, aligned to the clock.
+func newClockTrigger(d time.Duration) *Trigger {
+	t := NewTrigger()
+	t.rule = clockInterval{d: d}
+	return t
+}
+
+func (c clockInterval) next(start, after time.Time) time.Time {
+	loc := start.Location()
+	if after.Before(start) {
+		after = start
+	}
+	if (24*time.Hour)%c.d != 0 {
+		y, m, d := start.In(loc).Date()
+		base := time.Date(y, m, d, 0, 0, 0, 0, loc).Add(c.offset)
+		if after.Before(base) {
+			return base
+		}
+		return base.Add((after.Sub(base)/c.d + 1) * c.d)
+	}
+	a := after.In(loc)
+	y, m, d := a.Date()
+	first := c.offset % c.d
+	elapsed := clockOffset(a) + time.Duration(a.Nanosecond())
+	// Times of day skipped or repeated by a change of the UTC offset may
+	// resolve to a time which is not after the given time.
+	for i := 0; i < 3; i++ {
+		off := first
+		if elapsed >= first {
+			off += ((elapsed-first)/c.d + 1) * c.d
+		}
+		if off >= 24*time.Hour {
+			d, off = d+1, first
+		}
+		next := time.Date(y, m, d, 0, 0, 0, int(off), loc)
+		if next.After(after) {
+			return next
+		}
+		elapsed = off
+	}
+	return time.Time{}
+}
+
+// formatClock formats a number of minutes after midnight as "15:04".
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// containsWeekday returns whether days contains day.
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
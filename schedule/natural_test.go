@@ -0,0 +1,134 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseNatural(t *testing.T) {
+	// 2028-01-03 is a Monday.
+	start := time.Date(2028, 1, 3, 8, 7, 30, 0, time.UTC)
+	day := func(d, h, m int) time.Time {
+		return time.Date(2028, 1, d, h, m, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		phrase string
+		next   []time.Time
+	}{
+		{"every 15 minutes", []time.Time{day(3, 8, 15), day(3, 8, 30)}},
+		{"Every 15 Minutes.", []time.Time{day(3, 8, 15)}},
+		{"every minute", []time.Time{day(3, 8, 8)}},
+		{"every 30 seconds", []time.Time{day(3, 8, 8), day(3, 8, 8).Add(30 * time.Second)}},
+		{"every two hours", []time.Time{day(3, 10, 0), day(3, 12, 0)}},
+		{"every 7 hours", []time.Time{day(3, 14, 0), day(3, 21, 0), day(4, 4, 0)}},
+		{"hourly", []time.Time{day(3, 9, 0)}},
+		{"every 4 hours on weekends", []time.Time{day(8, 0, 0), day(8, 4, 0)}},
+		{"every day", []time.Time{day(4, 0, 0), day(5, 0, 0)}},
+		{"daily at 6:30pm", []time.Time{day(3, 18, 30), day(4, 18, 30)}},
+		{"every day at noon", []time.Time{day(3, 12, 0)}},
+		{"every day at midnight", []time.Time{day(4, 0, 0)}},
+		{"every day at 7 am", []time.Time{day(4, 7, 0)}},
+		{"every day at 12am", []time.Time{day(4, 0, 0)}},
+		{"every day at 9 p.m.", []time.Time{day(3, 21, 0)}},
+		{"every 3 days at 17:45", []time.Time{day(3, 17, 45), day(6, 17, 45)}},
+		{"every monday at 9am", []time.Time{day(3, 9, 0), day(10, 9, 0)}},
+		{"every Tuesday", []time.Time{day(4, 0, 0)}},
+		{"every other friday at 17:00", []time.Time{day(7, 17, 0), day(21, 17, 0)}},
+		{"every mon, wed and fri at noon", []time.Time{day(3, 12, 0), day(5, 12, 0), day(7, 12, 0)}},
+		{"every tuesday and thursday at 08:00", []time.Time{day(4, 8, 0), day(6, 8, 0)}},
+		{"every weekday at 2:30am", []time.Time{day(4, 2, 30), day(5, 2, 30), day(6, 2, 30), day(7, 2, 30), day(10, 2, 30)}},
+		{"every weekend at 10am", []time.Time{day(8, 10, 0), day(9, 10, 0)}},
+		{"on weekdays at 9am", []time.Time{day(3, 9, 0), day(4, 9, 0)}},
+		{"on mondays at 9am", []time.Time{day(3, 9, 0), day(10, 9, 0)}},
+		{"every 2 weeks on saturday at 10:00", []time.Time{day(8, 10, 0), day(22, 10, 0)}},
+		{"weekly", []time.Time{day(10, 0, 0)}},
+		{"on the 1st of every month at noon", []time.Time{time.Date(2028, 2, 1, 12, 0, 0, 0, time.UTC), time.Date(2028, 3, 1, 12, 0, 0, 0, time.UTC)}},
+		{"on the 15th day of each month", []time.Time{day(15, 0, 0)}},
+		{"on the 3rd", []time.Time{time.Date(2028, 2, 3, 0, 0, 0, 0, time.UTC)}},
+		{"on the last day of the month at 23:00", []time.Time{day(31, 23, 0), time.Date(2028, 2, 29, 23, 0, 0, 0, time.UTC)}},
+		{"on the first monday of the month at 10am", []time.Time{day(3, 10, 0), time.Date(2028, 2, 7, 10, 0, 0, 0, time.UTC)}},
+		{"on the last friday of every other month", []time.Time{day(28, 0, 0), time.Date(2028, 3, 31, 0, 0, 0, 0, time.UTC)}},
+		{"every 3 months on the last day", []time.Time{day(31, 0, 0), time.Date(2028, 4, 30, 0, 0, 0, 0, time.UTC)}},
+		{"every month on the 2nd tuesday at 9:15am", []time.Time{day(11, 9, 15)}},
+		{"monthly", []time.Time{time.Date(2028, 2, 3, 0, 0, 0, 0, time.UTC)}},
+		{"twice a day", []time.Time{day(3, 12, 0), day(4, 0, 0)}},
+		{"three times a day", []time.Time{day(3, 16, 0), day(4, 0, 0)}},
+		{"once a day", []time.Time{day(4, 0, 0)}},
+		{"4 times an hour", []time.Time{day(3, 8, 15), day(3, 8, 30)}},
+		{"once a week", []time.Time{day(10, 0, 0)}},
+	}
+	for _, test := range tests {
+		trigger, err := ParseNatural(test.phrase)
+		if err != nil {
+			t.Errorf("Could not parse %q: %v", test.phrase, err)
+			continue
+		}
+		trigger.From(start)
+		after := start
+		for _, next := range test.next {
+			if n := trigger.NextAfter(after); !n.Equal(next) {
+				t.Errorf("Next time for %q did not match. Got %v, expected %v", test.phrase, n, next)
+				break
+			}
+			after = next
+		}
+	}
+}
+
+func TestParseNaturalWindow(t *testing.T) {
+	trigger, err := ParseNatural("every 2 hours between 9am and 5pm")
+	if err != nil {
+		t.Fatalf("Could not parse phrase: %v", err)
+	}
+	// The phrase is aligned to the window regardless of the time it was
+	// parsed or is evaluated at.
+	after := time.Date(2028, 1, 3, 18, 30, 51, 0, time.Local)
+	day := func(d, h int) time.Time {
+		return time.Date(2028, 1, d, h, 0, 0, 0, time.Local)
+	}
+	for _, expected := range []time.Time{day(4, 9), day(4, 11), day(4, 13), day(4, 15), day(5, 9)} {
+		next := trigger.NextAfter(after)
+		if !next.Equal(expected) {
+			t.Fatalf("Next time did not match. Got %v, expected %v", next, expected)
+		}
+		after = next
+	}
+}
+
+func TestParseNaturalError(t *testing.T) {
+	tests := []struct {
+		phrase string
+		err    error
+		pos    int
+	}{
+		{"", ErrEmptySpec, 0},
+		{"sometimes", ErrUnsupportedPhrase, 0},
+		{"every fortnight", ErrUnsupportedPhrase, 6},
+		{"every 0 minutes", ErrUnsupportedPhrase, 6},
+		{"every 15 minutes at 9am", ErrUnsupportedPhrase, 17},
+		{"every day at 25:00", ErrUnsupportedPhrase, 13},
+		{"every day at 13pm", ErrUnsupportedPhrase, 13},
+		{"every day at 9", ErrUnsupportedPhrase, 13},
+		{"every monday and", ErrUnsupportedPhrase, 16},
+		{"on the 32nd", ErrUnsupportedPhrase, 7},
+		{"on the 6th monday", ErrUnsupportedPhrase, 7},
+		{"on the 1th", ErrUnsupportedPhrase, 7},
+		{"on the 1st of every week", ErrUnsupportedPhrase, 20},
+		{"five times a day", ErrUnsupportedPhrase, 13},
+		{"twice a week", ErrUnsupportedPhrase, 8},
+		{"every 2 hours between 9am", ErrUnsupportedPhrase, 25},
+		{"every day please", ErrUnsupportedPhrase, 10},
+	}
+	for _, test := range tests {
+		_, err := ParseNatural(test.phrase)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Error for %q was not a *ParseError. Got %v", test.phrase, err)
+			continue
+		}
+		if !errors.Is(err, test.err) || perr.Pos != test.pos {
+			t.Errorf("Error for %q did not match. Got %v at %d, expected %v at %d", test.phrase, perr.Err, perr.Pos, test.err, test.pos)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	// ErrEmptySpec is returned when parsing an empty schedule spec.
+	ErrEmptySpec = errors.New("schedule: empty schedule spec")
+	// ErrInvalidSpec is returned when a schedule spec is not recognized.
+	ErrInvalidSpec = errors.New("schedule: invalid schedule spec")
+	// ErrInvalidDuration is returned when a duration cannot be parsed.
+	ErrInvalidDuration = errors.New("schedule: invalid duration")
+	// ErrInvalidCron is returned when a cron expression cannot be parsed.
+	ErrInvalidCron = errors.New("schedule: invalid cron expression")
+	// ErrInvalidRRule is returned when a recurrence rule cannot be parsed.
+	ErrInvalidRRule = errors.New("schedule: invalid recurrence rule")
+	// ErrUnsupportedPhrase is returned when a natural language schedule
+	// phrase is not supported.
+	ErrUnsupportedPhrase = errors.New("schedule: unsupported schedule phrase")
+	// ErrInvalidSelector is returned when a label selector cannot be parsed.
+	ErrInvalidSelector = errors.New("schedule: invalid label selector")
+)
+
+// A ParseError represents an error parsing a schedule spec.
+// The spec is stored in .Spec, the byte offset of the offending token in
+// .Pos and a description of what was expected in .Expected. The sentinel
+// error describing the kind of spec, e.g. ErrInvalidCron, is stored in .Err
+// and can be matched using errors.Is.
+type ParseError struct {
+	Spec     string
+	Pos      int
+	Expected string
+	Err      error
+}
+
+// Error returns the error message.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v %q at position %d: expected %s", e.Err, e.Spec, e.Pos, e.Expected)
+}
+
+// Unwrap returns the sentinel error describing the kind of spec.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// descriptors maps the supported "@" descriptors to cron expressions.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseTrigger creates a new Trigger from a schedule spec. The following
+// forms are accepted:
+//
+//	5m, 1h30m                   an interval, as accepted by Every
+//	@every 5m                   an interval, as accepted by Every
+//	@hourly, @daily, @weekly    a predefined cron expression, also @midnight,
+//	                            @monthly, @yearly and @annually
+//	*/15 9-17 * * MON-FRI       a cron expression, as accepted by Cron
+//	FREQ=WEEKLY;BYDAY=MO        a recurrence rule, as accepted by RRule
+//
+// If the spec cannot be parsed, a *ParseError is returned.
+func ParseTrigger(spec string) (*Trigger, error) {
+	s := strings.TrimSpace(spec)
+	pos := strings.Index(spec, s)
+	upper := strings.ToUpper(s)
+	switch {
+	case s == "":
+		return nil, &ParseError{spec, 0, "a schedule", ErrEmptySpec}
+	case strings.HasPrefix(s, "@every"):
+		d := strings.TrimSpace(s[len("@every"):])
+		if !unicode.IsSpace(rune(s[len(s)-len(d)-1])) {
+			return nil, &ParseError{spec, pos + len("@every"), "a space", ErrInvalidSpec}
+		}
+		return parseEvery(spec, strings.LastIndex(spec, d), d)
+	case strings.HasPrefix(s, "@"):
+		cron, ok := descriptors[strings.ToLower(s)]
+		if !ok {
+			return nil, &ParseError{spec, pos, "one of @every, @yearly, @annually, @monthly, @weekly, @daily, @midnight or @hourly", ErrInvalidSpec}
+		}
+		c, err := parseCron(cron)
+		if err != nil {
+			return nil, err
+		}
+		return newRuleTrigger(c), nil
+	case strings.HasPrefix(upper, "RRULE:") || strings.HasPrefix(upper, "FREQ=") ||
+		strings.HasPrefix(upper, "DTSTART") || strings.HasPrefix(upper, "EXDATE"):
+		r, err := parseRRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		return newRuleTrigger(r), nil
+	case strings.HasPrefix(upper, "CRON_TZ=") || strings.IndexFunc(s, unicode.IsSpace) >= 0:
+		c, err := parseCron(spec)
+		if err != nil {
+			return nil, err
+		}
+		return newRuleTrigger(c), nil
+	case strings.IndexAny(s[:1], "0123456789.-+") >= 0:
+		return parseEvery(spec, pos, s)
+	}
+	return nil, &ParseError{spec, pos, "a duration, cron expression, recurrence rule or @ descriptor", ErrInvalidSpec}
+}
+
+// parseEvery creates a Trigger from the duration d found at pos in spec.
+func parseEvery(spec string, pos int, d string) (*Trigger, error) {
+	duration, err := time.ParseDuration(d)
+	if err != nil || duration <= 0 {
+		return nil, &ParseError{spec, pos, "a positive duration such as 5m or 1h30m", ErrInvalidDuration}
+	}
+	t := NewTrigger()
+	t.interval = duration
+	return t, nil
+}
+
+// newRuleTrigger creates a Trigger with the given recurrence.
+func newRuleTrigger(rule recurrence) *Trigger {
+	t := NewTrigger()
+	t.rule = rule
+	return t
+}
@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTrigger(t *testing.T) {
+	start := time.Date(2028, 1, 3, 8, 7, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"5m", start.Add(5 * time.Minute)},
+		{" 1h30m ", start.Add(90 * time.Minute)},
+		{"@every 5m", start.Add(5 * time.Minute)},
+		{"@hourly", time.Date(2028, 1, 3, 9, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2028, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@midnight", time.Date(2028, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2028, 1, 9, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2028, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@annually", time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 9-17 * * MON-FRI", time.Date(2028, 1, 3, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=DAILY;BYHOUR=12;BYMINUTE=0", time.Date(2028, 1, 3, 12, 0, 30, 0, time.UTC)},
+		{"RRULE:FREQ=DAILY;BYHOUR=12;BYMINUTE=0", time.Date(2028, 1, 3, 12, 0, 30, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		trigger, err := ParseTrigger(test.spec)
+		if err != nil {
+			t.Errorf("Could not parse %q: %v", test.spec, err)
+			continue
+		}
+		trigger.From(start)
+		if n := trigger.NextAfter(start); !n.Equal(test.next) {
+			t.Errorf("Next time for %q did not match. Got %v, expected %v", test.spec, n, test.next)
+		}
+	}
+}
+
+func TestParseTriggerError(t *testing.T) {
+	tests := []struct {
+		spec string
+		err  error
+		pos  int
+	}{
+		{"", ErrEmptySpec, 0},
+		{"  ", ErrEmptySpec, 0},
+		{"5x", ErrInvalidDuration, 0},
+		{"-5m", ErrInvalidDuration, 0},
+		{"@every", ErrInvalidSpec, 6},
+		{"@every 5", ErrInvalidDuration, 7},
+		{"@fortnightly", ErrInvalidSpec, 0},
+		{"soon", ErrInvalidSpec, 0},
+		{"* * * *", ErrInvalidCron, 7},
+		{"* * * * * *", ErrInvalidCron, 10},
+		{"*/15 25 * * *", ErrInvalidCron, 5},
+		{"0 1,2,x * * *", ErrInvalidCron, 6},
+		{"0 1-x * * *", ErrInvalidCron, 4},
+		{"*/0 * * * *", ErrInvalidCron, 2},
+		{"FREQ=DAILY;COUNT=0", ErrInvalidRRule, 17},
+		{"FREQ=FORTNIGHTLY", ErrInvalidRRule, 5},
+		{"RRULE:FREQ=DAILY;FOO=1", ErrInvalidRRule, 17},
+		{"RRULE:INTERVAL=2", ErrInvalidRRule, 16},
+	}
+	for _, test := range tests {
+		_, err := ParseTrigger(test.spec)
+		if !errors.Is(err, test.err) {
+			t.Errorf("Error for %q did not match. Got %v, expected %v", test.spec, err, test.err)
+			continue
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Error for %q was not a ParseError. Got %T", test.spec, err)
+			continue
+		}
+		if perr.Pos != test.pos {
+			t.Errorf("Error position for %q did not match. Got %d, expected %d", test.spec, perr.Pos, test.pos)
+		}
+		if perr.Expected == "" || perr.Error() == "" {
+			t.Errorf("Error for %q did not describe the expected token", test.spec)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package schedule
+
+import "time"
+
+// dispatchItem represents a job due to be run by a Queue.
+type dispatchItem struct {
+	job      *Job
+	priority int
+	since    time.Time
+	index    int
+}
+
+// dispatchHeap is a heap of due jobs, ordered by priority, then by how long
+// they have been waiting and then by their position in the Queue.
+type dispatchHeap []dispatchItem
+
+func (h dispatchHeap) Len() int {
+	return len(h)
+}
+
+func (h dispatchHeap) Less(a, b int) bool {
+	switch {
+	case h[a].priority != h[b].priority:
+		return h[a].priority > h[b].priority
+	case !h[a].since.Equal(h[b].since):
+		return h[a].since.Before(h[b].since)
+	}
+	return h[a].index < h[b].index
+}
+
+func (h dispatchHeap) Swap(a, b int) {
+	h[a], h[b] = h[b], h[a]
+}
+
+func (h *dispatchHeap) Push(x interface{}) {
+	*h = append(*h, x.(dispatchItem))
+}
+
+func (h *dispatchHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// Priority sets the priority of the job. When more jobs are due than a
+// Queue may run at the same time, jobs with a higher priority are started
+// first. By default, the priority of a job is 0. See Queue.MaxConcurrent.
+func (j *Job) Priority(n int) *Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.priority = n
+	return j
+}
+
+// priorityLevel returns the priority of the job.
+func (j *Job) priorityLevel() int {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return j.priority
+}
@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+// runNext runs the queue and waits for the result of the job it dispatched.
+// A result is only emitted once the job is no longer active in the queue.
+func runNext(t *testing.T, q *Queue) JobResult {
+	q.Run()
+	select {
+	case res := <-q.Results():
+		return res
+	case <-time.After(time.Second):
+		t.Fatal("Expected a job to be run")
+	}
+	return JobResult{}
+}
+
+// activeJobs returns the number of jobs running in the queue.
+func activeJobs(q *Queue) int {
+	q.pool.Lock()
+	defer q.pool.Unlock()
+	return len(q.active)
+}
+
+func TestQueue_RunPriority(t *testing.T) {
+	q := NewQueue()
+	q.MaxConcurrent(1)
+	for _, p := range []struct {
+		name     string
+		priority int
+	}{{"low", 0}, {"default", 0}, {"high", 10}, {"medium", 5}} {
+		j, err := NewJob(p.name, func(name string) string { return name }, p.name)
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		q.Add(j.Priority(p.priority))
+		j.Trigger()
+	}
+	var order []string
+	for i := 0; i < 4; i++ {
+		order = append(order, runNext(t, q).Name)
+	}
+	expected := []string{"high", "medium", "low", "default"}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Run order did not match. Got %v, expected %v", order, expected)
+			break
+		}
+	}
+}
+
+func TestQueue_RunMaxConcurrent(t *testing.T) {
+	q := NewQueue()
+	q.MaxConcurrent(2)
+	release := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		j, err := NewJob("test", func() bool {
+			<-release
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		q.Add(j)
+		j.Trigger()
+	}
+	for ran := 0; ran < 5; ran++ {
+		q.Run()
+		expected := 2
+		if 5-ran < expected {
+			expected = 5 - ran
+		}
+		if n := activeJobs(q); n != expected {
+			t.Fatalf("Number of concurrent jobs did not match. Got %d, expected %d", n, expected)
+		}
+		// Releasing a single job frees a single slot for the next Run.
+		release <- struct{}{}
+		select {
+		case <-q.Results():
+		case <-time.After(time.Second):
+			t.Fatal("Expected a released job to finish")
+		}
+	}
+	if n := activeJobs(q); n != 0 {
+		t.Errorf("Number of running jobs did not match. Got %d, expected 0", n)
+	}
+}
+
+func TestQueue_RunAging(t *testing.T) {
+	for _, aging := range []time.Duration{0, time.Minute} {
+		q := NewQueue()
+		q.MaxConcurrent(1)
+		q.Aging(aging)
+		high, err := NewJob("high", func() string { return "high" })
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		low, err := NewJob("low", func() string { return "low" })
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		q.Add(high.Priority(3))
+		q.Add(low)
+		high.Trigger()
+		low.Trigger()
+		// The low priority job has been waiting for an hour, which raises
+		// its priority above the high priority job unless aging is off.
+		q.pool.Lock()
+		q.waiting[low] = time.Now().Add(-time.Hour)
+		q.pool.Unlock()
+		expected := "high"
+		if aging > 0 {
+			expected = "low"
+		}
+		if name := runNext(t, q).Name; name != expected {
+			t.Errorf("Job run first with aging %v did not match. Got %s, expected %s", aging, name, expected)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package schedule
 
 import (
+	"container/heap"
+	"reflect"
 	"sync"
 	"time"
 )
@@ -9,23 +11,52 @@ import (
 type Queue struct {
 	Jobs      []*Job
 	errors    chan JobError
+	events    chan JobEvent
 	mutex     sync.RWMutex
+	name      string
 	results   chan JobResult
 	suspended bool
+	pool      sync.Mutex
+	limit     int
+	aging     time.Duration
+	active    map[*Job]bool
+	waiting   map[*Job]time.Time
+	throttled map[*Job]bool
+	slots     *slotPool
+	limiters  []*RateLimiter
+	tagged    *selectorLimiters
 }
 
 // NewQueue creates a new Queue.
-// By default, the Queue is initialized with a max results and error
-// buffer of 10. See MaxBufferedErrors and MaxBufferedResults.
+// By default, the Queue is initialized with a max results, error and event
+// buffer of 10, runs any number of jobs at the same time and raises the
+// priority of waiting jobs by one every 10 seconds. See MaxBufferedErrors,
+// MaxBufferedEvents, MaxBufferedResults, MaxConcurrent and Aging.
 func NewQueue() *Queue {
 	return &Queue{
 		Jobs:      make([]*Job, 0),
 		errors:    make(chan JobError, 10),
+		events:    make(chan JobEvent, 10),
 		results:   make(chan JobResult, 10),
 		suspended: false,
+		aging:     10 * time.Second,
+		active:    make(map[*Job]bool),
+		waiting:   make(map[*Job]time.Time),
+		throttled: make(map[*Job]bool),
 	}
 }
 
+// label returns the name the Queue was added to a Scheduler with, or
+// "unnamed" if it has not been added to one.
+func (q *Queue) label() string {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	if q.name == "" {
+		return "unnamed"
+	}
+	return q.name
+}
+
 // Add appends a job to this queue.
 // If the job is already present, the function returns without adding it.
 func (q *Queue) Add(job *Job) {
@@ -37,6 +68,7 @@ func (q *Queue) Add(job *Job) {
 		}
 	}
 	q.Jobs = append(q.Jobs, job)
+	job.observe(q)
 }
 
 // Errors returns the channel on which job errors are emitted.
@@ -46,6 +78,38 @@ func (q *Queue) Errors() chan JobError {
 	return q.errors
 }
 
+// Events returns the channel on which job state transitions are emitted.
+// If the buffer is full, new events are dropped.
+func (q *Queue) Events() chan JobEvent {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	return q.events
+}
+
+// Aging sets how fast the priority of jobs waiting to be run by the queue
+// rises, to keep jobs with a low priority from starving. The priority of a
+// waiting job is raised by one for every d it has waited.
+// If 0 or a negative value is provided, the priority of jobs is not raised.
+func (q *Queue) Aging(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	q.pool.Lock()
+	defer q.pool.Unlock()
+	q.aging = d
+}
+
+// MaxConcurrent sets the number of jobs the queue runs at the same time.
+// If 0 or a negative value is provided, the number of jobs is not limited.
+func (q *Queue) MaxConcurrent(n int) {
+	if n < 0 {
+		n = 0
+	}
+	q.pool.Lock()
+	defer q.pool.Unlock()
+	q.limit = n
+}
+
 // MaxBufferedErrors sets the buffer length of the job errors channel.
 func (q *Queue) MaxBufferedErrors(n int) {
 	errors := make(chan JobError, n)
@@ -60,6 +124,21 @@ func (q *Queue) MaxBufferedErrors(n int) {
 	q.errors = errors
 }
 
+// MaxBufferedEvents sets the buffer length of the job events channel.
+func (q *Queue) MaxBufferedEvents(n int) {
+	events := make(chan JobEvent, n)
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	close(q.events)
+	for event := range q.events {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+	q.events = events
+}
+
 // MaxBufferedResults sets the buffer length of the job results channel.
 func (q *Queue) MaxBufferedResults(n int) {
 	results := make(chan JobResult, n)
@@ -74,6 +153,25 @@ func (q *Queue) MaxBufferedResults(n int) {
 	q.results = results
 }
 
+// Remove removes a job from this queue. If the job is currently running, the
+// run is not interrupted.
+// If the job is not present, the function returns without removing it.
+func (q *Queue) Remove(job *Job) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for i, j := range q.Jobs {
+		if job == j {
+			q.Jobs = append(q.Jobs[:i:i], q.Jobs[i+1:]...)
+			job.unobserve(q)
+			break
+		}
+	}
+	q.pool.Lock()
+	defer q.pool.Unlock()
+	delete(q.waiting, job)
+	delete(q.throttled, job)
+}
+
 // Results returns the channel on which job results are emitted.
 func (q *Queue) Results() chan JobResult {
 	q.mutex.RLock()
@@ -91,34 +189,130 @@ func (q *Queue) Resume() {
 // Run checks all jobs if they should be run and triggers each of them in
 // their own goroutine. Job results and errors are emitted to the Results and
 // Errors channels respectively.
-// If the queue is suspended, no jobs are checked.
+// If the queue is suspended, no jobs are checked. Jobs that are running,
+// paused, disabled or completed are skipped, and jobs that will not run
+// again are marked as completed. Jobs with a pending run requested by
+// Job.Trigger are run regardless of their schedule, even once completed.
+// If more jobs are due than the queue may run at the same time, the jobs
+// with the highest priority are started first, while the rest wait for the
+// next Run. When the queue belongs to a Scheduler limiting the number of jobs
+// run across its queues, the queue also waits for its fair share of slots.
+// Runs over the budget of a RateLimiter are either delayed until it has
+// budget again or skipped, emitting an EventDelayed or EventSkipped event.
+// See MaxConcurrent, Job.Priority, Scheduler.MaxConcurrent and RateLimit.
 func (q *Queue) Run() {
+	// Completing a job emits its state transition to every queue observing
+	// it, which takes the queue mutex, so jobs are only completed once the
+	// locks held by run are released.
+	for _, job := range q.run() {
+		job.complete()
+	}
+}
+
+// run starts the jobs that are due, returning the jobs that will not run
+// again.
+func (q *Queue) run() (completed []*Job) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
-	if !q.suspended {
-		for _, job := range q.Jobs {
+	if q.suspended {
+		q.slots.wait(q, q.name, 0)
+		return nil
+	}
+	now := time.Now()
+	q.pool.Lock()
+	defer q.pool.Unlock()
+	waiting := make(map[*Job]time.Time)
+	throttled := make(map[*Job]bool)
+	due := &dispatchHeap{}
+	for i, job := range q.Jobs {
+		if q.active[job] || !job.runnable() {
+			continue
+		}
+		if !job.requested() {
 			next := job.NextRun()
-			if !next.IsZero() && !next.After(time.Now()) {
-				go func(job *Job) {
-					res, err := job.Run()
-					if len(res) > 0 {
-						q.mutex.Lock()
-						select {
-						case q.results <- JobResult{job.Name, res}:
-						}
-						q.mutex.Unlock()
-					}
-					if err != nil {
-						q.mutex.Lock()
-						select {
-						case q.errors <- JobError{job.Name, err}:
-						}
-						q.mutex.Unlock()
-					}
-				}(job)
+			if next.IsZero() {
+				completed = append(completed, job)
+				continue
 			}
+			if next.After(now) {
+				continue
+			}
+		}
+		since, ok := q.waiting[job]
+		if !ok {
+			since = now
 		}
+		waiting[job] = since
+		*due = append(*due, dispatchItem{job, q.priority(job, since, now), since, i})
 	}
+	heap.Init(due)
+	q.slots.wait(q, q.name, due.Len())
+	for due.Len() > 0 && (q.limit == 0 || len(q.active) < q.limit) && q.slots.acquire(q, q.name) {
+		job := heap.Pop(due).(dispatchItem).job
+		if mode, ok := q.throttle(job, now); !ok {
+			q.slots.release(q)
+			state := job.State()
+			// Only scheduled runs are skipped, as requested runs would be
+			// lost along with their arguments.
+			if mode == LimitSkip && !job.requested() {
+				delete(waiting, job)
+				job.skip(now)
+				q.post(JobEvent{Name: job.Name, From: state, To: state, Time: now, Type: EventSkipped})
+				continue
+			}
+			// A delayed run is reported once, rather than on every Run.
+			throttled[job] = true
+			if !q.throttled[job] {
+				q.post(JobEvent{Name: job.Name, From: state, To: state, Time: now, Type: EventDelayed})
+			}
+			continue
+		}
+		delete(waiting, job)
+		q.active[job] = true
+		if r, ok := job.take(); ok {
+			q.dispatch(job, r.requested, r.args)
+		} else {
+			q.dispatch(job, job.NextRun(), job.args)
+		}
+	}
+	q.waiting = waiting
+	q.throttled = throttled
+	return completed
+}
+
+// dispatch runs the job with args in its own goroutine, emitting its results
+// and errors to the Results and Errors channels, and requests a run of the
+// job chained to it, if any. See Job.Then and Job.OnFailure.
+func (q *Queue) dispatch(job *Job, scheduled time.Time, args []reflect.Value) {
+	go func() {
+		res, record, err := job.run(scheduled, args)
+		q.pool.Lock()
+		delete(q.active, job)
+		q.pool.Unlock()
+		q.slots.release(q)
+		if len(res) > 0 {
+			q.mutex.Lock()
+			select {
+			case q.results <- JobResult{job.Name, res}:
+			}
+			q.mutex.Unlock()
+		}
+		if err != nil {
+			q.mutex.Lock()
+			select {
+			case q.errors <- JobError{job.Name, err}:
+			}
+			q.mutex.Unlock()
+		}
+		// The chained job follows the outcome of the run, which does not
+		// fail when only its record could not be saved.
+		if next, args := job.successor(res, record.Error); next != nil {
+			if !next.observed() {
+				q.Add(next)
+			}
+			next.TriggerWith(args...)
+		}
+	}()
 }
 
 // Suspend will suspend the queue and no jobs will be run until Resumed.
@@ -134,3 +328,30 @@ func (q *Queue) Suspended() bool {
 	defer q.mutex.RUnlock()
 	return q.suspended
 }
+
+// priority returns the priority of a job that has been waiting to be run
+// since the given time, raised by aging.
+// The caller must hold the pool mutex.
+func (q *Queue) priority(job *Job, since, now time.Time) int {
+	p := job.priorityLevel()
+	if q.aging > 0 {
+		p += int(now.Sub(since) / q.aging)
+	}
+	return p
+}
+
+// emit sends a job event to the events channel without blocking.
+func (q *Queue) emit(event JobEvent) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	q.post(event)
+}
+
+// post sends a job event to the events channel without blocking.
+// The caller must hold the mutex.
+func (q *Queue) post(event JobEvent) {
+	select {
+	case q.events <- event:
+	default:
+	}
+}
@@ -50,6 +50,33 @@ func TestQueue_Errors(t *testing.T) {
 	}
 }
 
+func TestQueue_Events(t *testing.T) {
+	q := NewQueue()
+	if q.Events() == nil {
+		t.Error("Event buffer was nil.")
+	}
+}
+
+func TestQueue_EventsTransition(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() { return })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	q.Add(j)
+	j.Pause()
+	if len(q.events) != 1 {
+		t.Fatalf(
+			"Number of events in buffer did not match. Got %d, expected 1.",
+			len(q.events),
+		)
+	}
+	e := <-q.events
+	if e.Name != "test" || e.From != StateScheduled || e.To != StatePaused {
+		t.Errorf("Event did not match. Got %+v", e)
+	}
+}
+
 func TestQueue_MaxBufferedErrors(t *testing.T) {
 	q := NewQueue()
 	q.MaxBufferedErrors(50)
@@ -73,6 +100,29 @@ func TestQueue_MaxBufferedErrorsCopy(t *testing.T) {
 	}
 }
 
+func TestQueue_MaxBufferedEvents(t *testing.T) {
+	q := NewQueue()
+	q.MaxBufferedEvents(50)
+	if cap(q.events) != 50 {
+		t.Errorf(
+			"Event buffer for Queue did not match. Got %d, expected 50.",
+			cap(q.events),
+		)
+	}
+}
+
+func TestQueue_MaxBufferedEventsCopy(t *testing.T) {
+	q := NewQueue()
+	q.events <- JobEvent{Name: "test"}
+	q.MaxBufferedEvents(50)
+	if len(q.events) != 1 {
+		t.Errorf(
+			"Number of events in buffer did not match. Got %d, expected 1.",
+			len(q.events),
+		)
+	}
+}
+
 func TestQueue_MaxBufferedResults(t *testing.T) {
 	q := NewQueue()
 	q.MaxBufferedResults(50)
@@ -142,6 +192,85 @@ func TestQueue_Run(t *testing.T) {
 	}
 }
 
+func TestQueue_RunExpired(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() string { return "test" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	start := time.Now().Add(-time.Hour)
+	j.Schedule().Every("1m").Between(start, start.Add(30*time.Second))
+	q.Add(j)
+	q.Run()
+	if j.State() != StateCompleted {
+		t.Errorf("State did not match. Got %s, expected %s", j.State(), StateCompleted)
+	}
+}
+
+func TestQueue_RunExpiredContended(t *testing.T) {
+	q := NewQueue()
+	start := time.Now().Add(-time.Hour)
+	var jobs []*Job
+	for i := 0; i < 200; i++ {
+		j, err := NewJob("test", func() {})
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		j.Schedule().Every("1m").Between(start, start.Add(30*time.Second))
+		q.Add(j)
+		jobs = append(jobs, j)
+	}
+	stop, started := make(chan struct{}), make(chan struct{})
+	defer close(stop)
+	go func() {
+		close(started)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				q.Add(jobs[0])
+			}
+		}
+	}()
+	<-started
+	done := make(chan struct{})
+	go func() {
+		q.Run()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return while the queue was contended")
+	}
+	for _, j := range jobs {
+		if j.State() != StateCompleted {
+			t.Fatalf("State did not match. Got %s, expected %s", j.State(), StateCompleted)
+		}
+	}
+}
+
+func TestQueue_RunPaused(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() string { return "test" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	j.Schedule().Every("1ms").Limit(1)
+	j.Pause()
+	q.Add(j)
+	time.Sleep(10 * time.Millisecond)
+	q.Run()
+	time.Sleep(10 * time.Millisecond)
+	if len(q.results) != 0 {
+		t.Errorf(
+			"Results in buffer did not match. Got %d, expected 0",
+			len(q.results),
+		)
+	}
+}
+
 func TestQueue_Suspend(t *testing.T) {
 	q := NewQueue()
 	q.Suspend()
@@ -149,3 +278,125 @@ func TestQueue_Suspend(t *testing.T) {
 		t.Error("Queue is active after Suspend")
 	}
 }
+
+func TestQueue_RunTrigger(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() string { return "test" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	q.Add(j)
+	q.Run()
+	time.Sleep(10 * time.Millisecond)
+	if len(q.results) != 0 {
+		t.Fatalf("Unscheduled job was run")
+	}
+	j.Trigger()
+	j.Trigger()
+	q.Run()
+	time.Sleep(10 * time.Millisecond)
+	q.Run()
+	time.Sleep(10 * time.Millisecond)
+	if len(q.results) != 1 {
+		t.Errorf(
+			"Number of results in buffer did not match. Got %d, expected 1.",
+			len(q.results),
+		)
+	}
+	if j.State() != StateScheduled {
+		t.Errorf("Job state did not match. Got %v, expected %v", j.State(), StateScheduled)
+	}
+}
+
+func TestQueue_RunTriggerPaused(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() string { return "test" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	q.Add(j)
+	j.Pause()
+	j.Trigger()
+	q.Run()
+	time.Sleep(10 * time.Millisecond)
+	if len(q.results) != 0 {
+		t.Fatalf("Paused job was run")
+	}
+	j.Resume()
+	q.Run()
+	time.Sleep(10 * time.Millisecond)
+	if len(q.results) != 1 {
+		t.Errorf(
+			"Number of results in buffer did not match. Got %d, expected 1.",
+			len(q.results),
+		)
+	}
+}
+
+func TestQueue_RunTriggerCompleted(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() string { return "test" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	j.Schedule().At(time.Now().Add(-time.Millisecond))
+	q.Add(j)
+	j.Run()
+	if j.State() != StateCompleted {
+		t.Fatalf("Job state did not match. Got %v, expected %v", j.State(), StateCompleted)
+	}
+	j.Trigger()
+	q.Run()
+	select {
+	case <-q.Results():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the completed job to be run when triggered")
+	}
+	if j.RunCount() != 2 {
+		t.Errorf("Run count did not match. Got %d, expected %d", j.RunCount(), 2)
+	}
+	if j.State() != StateCompleted {
+		t.Errorf("Job state did not match. Got %v, expected %v", j.State(), StateCompleted)
+	}
+}
+
+func TestJob_TriggerWithBounded(t *testing.T) {
+	j, err := NewJob("test", func(i int) int { return i })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	for i := 0; i < maxPendingRuns+50; i++ {
+		j.TriggerWith(i)
+	}
+	if n := len(j.pending); n != maxPendingRuns {
+		t.Fatalf("Number of pending runs did not match. Got %d, expected %d", n, maxPendingRuns)
+	}
+	r, _ := j.take()
+	if i := r.args[0].Interface(); i != 50 {
+		t.Errorf("Oldest pending run did not match. Got %v, expected %d", i, 50)
+	}
+}
+
+func TestQueue_RunTriggerOn(t *testing.T) {
+	q := NewQueue()
+	j, err := NewJob("test", func() string { return "test" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	q.Add(j)
+	ch := make(chan struct{})
+	j.TriggerOn(ch)
+	for i := 0; i < 3; i++ {
+		ch <- struct{}{}
+		time.Sleep(10 * time.Millisecond)
+		q.Run()
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(ch)
+	if len(q.results) != 3 {
+		t.Errorf(
+			"Number of results in buffer did not match. Got %d, expected 3.",
+			len(q.results),
+		)
+	}
+}
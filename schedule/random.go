@@ -0,0 +1,116 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// randomWindow is a recurrence firing once at a random time within each of
+// a series of recurring windows. The recurrence produces the start of every
+// window, while the random time within it is chosen by the Trigger. See
+// Trigger.delay.
+type randomWindow struct {
+	period time.Duration
+	daily  bool
+	from   time.Duration
+	width  time.Duration
+}
+
+// RandomlyEvery schedules a single run of the job at a random time within
+// every period. Periods are counted from midnight of the start date of the
+// Trigger, e.g. Trigger.RandomlyEvery("1h") runs once per clock hour, at a
+// random minute.
+// The time chosen for a period stays the same for as long as the seed of the
+// Trigger does. See Seed. Periods starting before the start time are
+// skipped.
+// If the provided string cannot be parsed, the function will panic with
+// the error. If 0 or a negative period is provided, the job is not
+// scheduled.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) RandomlyEvery(str string) *Trigger {
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		panic(err)
+	}
+	t.interval = 0
+	t.rule = nil
+	if d > 0 {
+		t.rule = randomWindow{period: d, width: d}
+	}
+	return t.seeded()
+}
+
+// RandomlyBetween schedules a single run of the job every day, at a random
+// time between from and to. The values passed need to be in 24-hour "15:04"
+// format, in the location of the start time. If to is not after from, the
+// window spans midnight, e.g. Trigger.RandomlyBetween("23:00", "02:00").
+// The time chosen for a day stays the same for as long as the seed of the
+// Trigger does. See Seed. Windows starting before the start time are
+// skipped.
+// If the provided strings cannot be parsed, the function will panic with
+// the error.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) RandomlyBetween(from, to string) *Trigger {
+	f, err := time.Parse("15:04", from)
+	if err != nil {
+		panic(err)
+	}
+	e, err := time.Parse("15:04", to)
+	if err != nil {
+		panic(err)
+	}
+	width := clockOffset(e) - clockOffset(f)
+	if width <= 0 {
+		width += 24 * time.Hour
+	}
+	t.interval = 0
+	t.rule = randomWindow{daily: true, from: clockOffset(f), width: width}
+	return t.seeded()
+}
+
+// seeded sets a random seed for the Trigger, unless one is already set.
+func (t *Trigger) seeded() *Trigger {
+	if t.seed == 0 {
+		t.seed = time.Now().UnixNano()
+	}
+	return t
+}
+
+// spread returns the width of the random windows of the Trigger, if any.
+func (t *Trigger) spread() time.Duration {
+	if w, ok := t.rule.(randomWindow); ok {
+		return w.width
+	}
+	return 0
+}
+
+func (w randomWindow) next(start, after time.Time) time.Time {
+	if start.After(after) {
+		after = start.Add(-time.Nanosecond)
+	}
+	y, m, d := start.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, start.Location())
+	if !w.daily {
+		if after.Before(midnight) {
+			return midnight
+		}
+		n := after.Sub(midnight)/w.period + 1
+		return midnight.Add(n * w.period)
+	}
+	y, m, d = after.In(start.Location()).Date()
+	for i := -1; ; i++ {
+		next := time.Date(y, m, d+i, 0, 0, 0, 0, start.Location()).Add(w.from)
+		if next.After(after) {
+			return next
+		}
+	}
+}
+
+func (w randomWindow) describe(start time.Time) string {
+	if !w.daily {
+		return "once every " + describeDuration(w.period) + " at a random time"
+	}
+	end := w.from + w.width
+	return fmt.Sprintf("once a day at a random time between %02d:%02d and %02d:%02d", int(w.from.Hours()), int(w.from.Minutes())%60,
+		int(end.Hours())%24, int(end.Minutes())%60) + describeLocation(start.Location())
+}
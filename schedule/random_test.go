@@ -0,0 +1,66 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrigger_RandomlyEvery(t *testing.T) {
+	start := time.Date(2028, 1, 3, 8, 7, 30, 0, time.UTC)
+	trigger := NewTrigger().From(start).Seed(42).RandomlyEvery("1h")
+	after := start
+	for hour := 9; hour < 20; hour++ {
+		next := trigger.NextAfter(after)
+		if next.Hour() != hour || next.Day() != 3 {
+			t.Errorf("Next time was outside the window. Got %v, expected hour %d", next, hour)
+		}
+		if n := trigger.NextAfter(next.Add(-time.Nanosecond)); !n.Equal(next) {
+			t.Errorf("Next time was not stable within the window. Got %v, expected %v", n, next)
+		}
+		if n := trigger.NextAfter(time.Date(2028, 1, 3, hour, 0, 0, 0, time.UTC)); !n.Equal(next) {
+			t.Errorf("Next time was not stable within the window. Got %v, expected %v", n, next)
+		}
+		after = next
+	}
+}
+
+func TestTrigger_RandomlyEverySeed(t *testing.T) {
+	start := time.Date(2028, 1, 3, 8, 0, 0, 0, time.UTC)
+	a := NewTrigger().From(start).Seed(1).RandomlyEvery("24h")
+	b := NewTrigger().From(start).Seed(1).RandomlyEvery("24h")
+	c := NewTrigger().From(start).Seed(2).RandomlyEvery("24h")
+	if !a.NextAfter(start).Equal(b.NextAfter(start)) {
+		t.Errorf("Next time did not match for the same seed. Got %v and %v", a.NextAfter(start), b.NextAfter(start))
+	}
+	if a.NextAfter(start).Equal(c.NextAfter(start)) {
+		t.Errorf("Next time matched for different seeds. Got %v", a.NextAfter(start))
+	}
+	if NewTrigger().RandomlyEvery("0s").scheduled() {
+		t.Errorf("Trigger with an empty period was scheduled")
+	}
+}
+
+func TestTrigger_RandomlyBetween(t *testing.T) {
+	start := time.Date(2028, 1, 3, 3, 0, 0, 0, time.UTC)
+	trigger := NewTrigger().From(start).Seed(42).RandomlyBetween("01:00", "05:00")
+	after := start
+	for day := 4; day < 14; day++ {
+		next := trigger.NextAfter(after)
+		if next.Day() != day || next.Hour() < 1 || next.Hour() >= 5 {
+			t.Errorf("Next time was outside the window. Got %v, expected day %d", next, day)
+		}
+		after = next
+	}
+	trigger = NewTrigger().From(start).RandomlyBetween("23:00", "02:00")
+	after = start
+	for i := 0; i < 10; i++ {
+		next := trigger.NextAfter(after)
+		if next.Hour() < 23 && next.Hour() >= 2 {
+			t.Errorf("Next time was outside the window. Got %v", next)
+		}
+		if next.Sub(after) > 27*time.Hour {
+			t.Errorf("Next time skipped a window. Got %v after %v", next, after)
+		}
+		after = next
+	}
+}
@@ -0,0 +1,232 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+)
+
+// A LimitMode represents what happens to a run of a job when the budget of
+// its RateLimiter is exhausted.
+type LimitMode int
+
+const (
+	// LimitDelay keeps the run waiting until the limiter has budget again.
+	LimitDelay LimitMode = iota
+	// LimitSkip drops the run. A scheduled run is skipped until the next
+	// scheduled time, while runs requested by Job.Trigger or Job.TriggerWith
+	// are delayed as with LimitDelay.
+	LimitSkip
+)
+
+// String returns the name of the limit mode.
+func (m LimitMode) String() string {
+	switch m {
+	case LimitDelay:
+		return "delay"
+	case LimitSkip:
+		return "skip"
+	}
+	return "unknown"
+}
+
+// A RateLimiter represents a token bucket limiting how often jobs are run.
+// Every run dispatched by a queue takes a token from the bucket, and tokens
+// are added back at a steady rate. A RateLimiter may be attached to any
+// number of jobs and queues, or to every job with a set of labels, which
+// then share its budget, e.g. to cap the jobs calling the same API at a
+// number of calls per second combined.
+// See Job.RateLimit, Queue.RateLimit and Scheduler.RateLimit.
+type RateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	mode   LimitMode
+}
+
+// NewRateLimiter creates a new RateLimiter allowing n runs per period, e.g.
+// NewRateLimiter(10, time.Second).
+// By default, the RateLimiter allows bursts of up to n runs and delays runs
+// when its budget is exhausted. See Burst and Mode.
+// If 0 or a negative n is provided, it will be set to 1, and if 0 or a
+// negative period is provided, it will be set to one second.
+func NewRateLimiter(n int, per time.Duration) *RateLimiter {
+	if n < 1 {
+		n = 1
+	}
+	if per <= 0 {
+		per = time.Second
+	}
+	return &RateLimiter{
+		rate:   float64(n) / per.Seconds(),
+		burst:  float64(n),
+		tokens: float64(n),
+	}
+}
+
+// Burst sets the number of runs the RateLimiter allows at once after being
+// idle. If 0 or a negative value is provided, it will be set to 1.
+func (l *RateLimiter) Burst(n int) *RateLimiter {
+	if n < 1 {
+		n = 1
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.burst = float64(n)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	return l
+}
+
+// Mode sets whether runs are delayed or skipped when the budget of the
+// RateLimiter is exhausted.
+func (l *RateLimiter) Mode(mode LimitMode) *RateLimiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.mode = mode
+	return l
+}
+
+// Allow takes a token from the RateLimiter, returning false if its budget
+// is exhausted.
+func (l *RateLimiter) Allow() bool {
+	return l.take(time.Now())
+}
+
+// take takes a token from the bucket at the given time, returning false if
+// the bucket is empty.
+func (l *RateLimiter) take(now time.Time) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// refund returns a token taken from the bucket.
+func (l *RateLimiter) refund() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// limitMode returns the limit mode of the RateLimiter.
+func (l *RateLimiter) limitMode() LimitMode {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.mode
+}
+
+// RateLimit attaches a RateLimiter to the job, limiting how often it is run
+// by its queues. A job may have several limiters, in which case every one of
+// them needs to have budget for a run. Runs through Job.Run are not limited.
+func (j *Job) RateLimit(l *RateLimiter) *Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.limiters = append(j.limiters, l)
+	return j
+}
+
+// rateLimiters returns the limiters attached to the job.
+func (j *Job) rateLimiters() []*RateLimiter {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return append([]*RateLimiter(nil), j.limiters...)
+}
+
+// RateLimit attaches a RateLimiter to the queue, limiting how often the
+// jobs of the queue are run combined.
+func (q *Queue) RateLimit(l *RateLimiter) {
+	q.pool.Lock()
+	defer q.pool.Unlock()
+	q.limiters = append(q.limiters, l)
+}
+
+// selectorLimiter represents a RateLimiter applying to the jobs matching a
+// label selector.
+type selectorLimiter struct {
+	selector *Selector
+	limiter  *RateLimiter
+}
+
+// selectorLimiters represents the limiters applying to the jobs matching
+// label selectors, shared by the queues of a Scheduler.
+type selectorLimiters struct {
+	mutex    sync.RWMutex
+	limiters []selectorLimiter
+}
+
+// match returns the limiters applying to a job with the given labels.
+func (s *selectorLimiters) match(labels map[string]string) []*RateLimiter {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var limiters []*RateLimiter
+	for _, l := range s.limiters {
+		if l.selector.Matches(labels) {
+			limiters = append(limiters, l.limiter)
+		}
+	}
+	return limiters
+}
+
+// RateLimit attaches a RateLimiter to every job matching the label selector
+// in the queues of this Scheduler, e.g. Scheduler.RateLimit("api=github", l),
+// so that the jobs share its budget. The selector is matched against the
+// labels of a job every time it is dispatched, so jobs added or tagged later
+// are limited as well. See ParseSelector and Job.Tags.
+// If the selector could not be parsed, an error is returned.
+func (s *Scheduler) RateLimit(selector string, l *RateLimiter) error {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+	s.tagged.mutex.Lock()
+	defer s.tagged.mutex.Unlock()
+	s.tagged.limiters = append(s.tagged.limiters, selectorLimiter{sel, l})
+	return nil
+}
+
+// throttle takes a token from every limiter of the job, the queue and the
+// label selectors matching the job, returning the mode of the first
+// exhausted limiter and false if the job may not be run now. Tokens taken
+// from other limiters are then returned.
+// The caller must hold the pool mutex.
+func (q *Queue) throttle(job *Job, now time.Time) (LimitMode, bool) {
+	seen := make(map[*RateLimiter]bool)
+	var taken []*RateLimiter
+	limiters := append(job.rateLimiters(), q.limiters...)
+	limiters = append(limiters, q.tagged.match(job.Labels())...)
+	for _, l := range limiters {
+		if seen[l] {
+			continue
+		}
+		seen[l] = true
+		if !l.take(now) {
+			for _, t := range taken {
+				t.refund()
+			}
+			return l.limitMode(), false
+		}
+		taken = append(taken, l)
+	}
+	return LimitDelay, true
+}
@@ -0,0 +1,205 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Take(t *testing.T) {
+	l := NewRateLimiter(2, time.Second)
+	now := time.Now()
+	for i, test := range []struct {
+		offset   time.Duration
+		expected bool
+	}{
+		{0, true},
+		{0, true},
+		{0, false},
+		{250 * time.Millisecond, false},
+		{500 * time.Millisecond, true},
+		{500 * time.Millisecond, false},
+		{time.Hour, true},
+		{time.Hour, true},
+		{time.Hour, false},
+	} {
+		if actual := l.take(now.Add(test.offset)); actual != test.expected {
+			t.Errorf("Take %d did not match. Got %v, expected %v", i, actual, test.expected)
+		}
+	}
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	l := NewRateLimiter(10, time.Second).Burst(1)
+	now := time.Now()
+	if !l.take(now) {
+		t.Fatal("Expected the first take to succeed")
+	}
+	if l.take(now) {
+		t.Error("Expected the burst to be limited to 1")
+	}
+	if !l.take(now.Add(time.Second)) || l.take(now.Add(time.Second)) {
+		t.Error("Expected an idle limiter to refill up to its burst only")
+	}
+}
+
+func TestRateLimiter_Defaults(t *testing.T) {
+	l := NewRateLimiter(0, 0)
+	if l.rate != 1 || l.burst != 1 {
+		t.Errorf("Defaults did not match. Got rate %v and burst %v, expected 1 and 1", l.rate, l.burst)
+	}
+	if l.limitMode() != LimitDelay {
+		t.Errorf("Mode did not match. Got %v, expected %v", l.limitMode(), LimitDelay)
+	}
+}
+
+func TestQueue_RunRateLimitDelay(t *testing.T) {
+	q := NewQueue()
+	l := NewRateLimiter(1, time.Hour)
+	runs := make(chan string, 2)
+	var jobs []*Job
+	for _, name := range []string{"a", "b"} {
+		name := name
+		j, err := NewJob(name, func() { runs <- name })
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		q.Add(j.RateLimit(l))
+		jobs = append(jobs, j)
+	}
+	jobs[0].Priority(1)
+	jobs[0].Trigger()
+	jobs[1].Trigger()
+	q.Run()
+	q.Run()
+	select {
+	case name := <-runs:
+		if name != "a" {
+			t.Errorf("Run job did not match. Got %s, expected %s", name, "a")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a job to be run")
+	}
+	select {
+	case name := <-runs:
+		t.Fatalf("Expected job %s to be delayed", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if !jobs[1].requested() {
+		t.Error("Expected the delayed run to remain pending")
+	}
+	delayed := 0
+	for len(q.Events()) > 0 {
+		event := <-q.Events()
+		if event.Type == EventDelayed {
+			delayed++
+			if event.Name != "b" || event.From != event.To {
+				t.Errorf("Delayed event did not match. Got %+v", event)
+			}
+		}
+	}
+	if delayed != 1 {
+		t.Errorf("Number of delayed events did not match. Got %d, expected %d", delayed, 1)
+	}
+}
+
+func TestQueue_RunRateLimitSkip(t *testing.T) {
+	q := NewQueue()
+	q.RateLimit(NewRateLimiter(1, time.Hour).Mode(LimitSkip))
+	j, err := NewJob("test", func(s string) string { return s }, "scheduled")
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	j.Schedule().Every("1ms")
+	q.Add(j)
+	time.Sleep(time.Until(j.NextRun()))
+	q.Run()
+	// The result is emitted once the run is no longer active in the queue.
+	select {
+	case <-q.Results():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the first run to be dispatched")
+	}
+	time.Sleep(time.Until(j.NextRun()))
+	q.Run()
+	if next := j.NextRun(); next.Before(time.Now().Add(-time.Millisecond)) {
+		t.Errorf("Expected the scheduled run to be skipped. Next run %v", next)
+	}
+	j.TriggerWith("requested")
+	q.Run()
+	if !j.requested() {
+		t.Error("Expected the requested run to be delayed rather than skipped")
+	}
+	events := make(map[EventType]int)
+	for len(q.Events()) > 0 {
+		events[(<-q.Events()).Type]++
+	}
+	if events[EventSkipped] != 1 || events[EventDelayed] != 1 {
+		t.Errorf("Throttling events did not match. Got %v", events)
+	}
+	if j.RunCount() != 1 {
+		t.Errorf("Run count did not match. Got %d, expected %d", j.RunCount(), 1)
+	}
+}
+
+func TestScheduler_RateLimit(t *testing.T) {
+	s := NewScheduler()
+	s.Queue("other", NewQueue())
+	if err := s.RateLimit("api=github", NewRateLimiter(1, time.Hour)); err != nil {
+		t.Fatalf("Scheduler errored on RateLimit: %v", err)
+	}
+	if err := s.RateLimit("api in", NewRateLimiter(1, time.Hour)); err == nil {
+		t.Error("Expected an invalid selector to return an error")
+	}
+	runs := make(chan string, 3)
+	jobs := make(map[string]*Job)
+	for _, test := range []struct {
+		name, queue, api string
+	}{
+		{"issues", "default", "github"},
+		{"pulls", "other", "github"},
+		{"tickets", "other", "jira"},
+	} {
+		name := test.name
+		j, err := NewJob(name, func() { runs <- name })
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		// The jobs are tagged after the limiter was attached.
+		s.AddToQueue(test.queue, j.Tags(map[string]string{"api": test.api}))
+		j.Trigger()
+		jobs[name] = j
+	}
+	s.Queues["default"].Run()
+	s.Queues["other"].Run()
+	ran := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case name := <-runs:
+			ran[name] = true
+		case <-time.After(time.Second):
+			t.Fatalf("Expected two jobs to be run. Got %v", ran)
+		}
+	}
+	if !ran["issues"] || !ran["tickets"] {
+		t.Errorf("Run jobs did not match. Got %v", ran)
+	}
+	if !jobs["pulls"].requested() {
+		t.Error("Expected the run of pulls to be delayed by the shared limiter")
+	}
+}
+
+func TestEventType_String(t *testing.T) {
+	for _, test := range []struct {
+		t        EventType
+		expected string
+	}{
+		{EventTransition, "transition"},
+		{EventDelayed, "delayed"},
+		{EventSkipped, "skipped"},
+		{EventType(-1), "unknown"},
+	} {
+		if actual := test.t.String(); actual != test.expected {
+			t.Errorf("String did not match. Got %s, expected %s", actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// A recurrence represents a rule producing the scheduled times of a Trigger.
+type recurrence interface {
+	// next returns the first scheduled time after the given time, counting
+	// from the start time of the Trigger. If there are no more scheduled
+	// times, a zeroed time.Time is returned.
+	next(start, after time.Time) time.Time
+	// describe returns an English description of the recurrence, in lower
+	// case, given the start time of the Trigger.
+	describe(start time.Time) string
+}
+
+// timeList is a recurrence firing at a fixed set of times.
+type timeList []time.Time
+
+// newTimeList creates a sorted timeList from the given times.
+func newTimeList(ts []time.Time) timeList {
+	list := make(timeList, len(ts))
+	copy(list, ts)
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].Before(list[b])
+	})
+	return list
+}
+
+func (l timeList) next(start, after time.Time) time.Time {
+	i := sort.Search(len(l), func(i int) bool {
+		return l[i].After(after)
+	})
+	if i < len(l) {
+		return l[i]
+	}
+	return time.Time{}
+}
+
+// delay is a recurrence firing once, a fixed duration after the start time.
+type delay time.Duration
+
+func (d delay) next(start, after time.Time) time.Time {
+	if next := start.Add(time.Duration(d)); next.After(after) {
+		return next
+	}
+	return time.Time{}
+}
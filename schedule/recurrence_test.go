@@ -0,0 +1,35 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeList_Next(t *testing.T) {
+	now := time.Now()
+	l := newTimeList([]time.Time{now.Add(2 * time.Hour), now.Add(time.Hour)})
+	tests := []struct {
+		after time.Time
+		next  time.Time
+	}{
+		{now, now.Add(time.Hour)},
+		{now.Add(time.Hour), now.Add(2 * time.Hour)},
+		{now.Add(2 * time.Hour), time.Time{}},
+	}
+	for _, test := range tests {
+		if n := l.next(now, test.after); !n.Equal(test.next) {
+			t.Errorf("Next time did not match. Got %v, expected %v", n, test.next)
+		}
+	}
+}
+
+func TestDelay_Next(t *testing.T) {
+	now := time.Now()
+	d := delay(time.Hour)
+	if n := d.next(now, now); !n.Equal(now.Add(time.Hour)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, now.Add(time.Hour))
+	}
+	if n := d.next(now, now.Add(time.Hour)); !n.IsZero() {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, time.Time{})
+	}
+}
@@ -0,0 +1,524 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rruleFreq represents the FREQ rule part of an RRULE.
+type rruleFreq int
+
+const (
+	freqYearly rruleFreq = iota
+	freqMonthly
+	freqWeekly
+	freqDaily
+	freqHourly
+	freqMinutely
+)
+
+// maxRRulePeriods is the number of periods searched for the next occurrence
+// of an rrule before giving up.
+const maxRRulePeriods = 100000
+
+var rruleFreqs = map[string]rruleFreq{
+	"YEARLY":   freqYearly,
+	"MONTHLY":  freqMonthly,
+	"WEEKLY":   freqWeekly,
+	"DAILY":    freqDaily,
+	"HOURLY":   freqHourly,
+	"MINUTELY": freqMinutely,
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// rruleDay represents a single BYDAY value, e.g. "-1FR".
+// If n is 0, every matching weekday in the period is included.
+type rruleDay struct {
+	n   int
+	day time.Weekday
+}
+
+// rrule is a recurrence following an iCalendar recurrence rule, as
+// described in RFC 5545 section 3.3.10.
+type rrule struct {
+	freq       rruleFreq
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []rruleDay
+	byMonthDay []int
+	byMonth    []int
+	bySetPos   []int
+	byHour     []int
+	byMinute   []int
+	wkst       time.Weekday
+	dtstart    time.Time
+	exdates    []time.Time
+}
+
+// RRule sets the recurrence for the Trigger from an iCalendar recurrence
+// rule, as described in RFC 5545. The value passed may either be a bare rule,
+// e.g. "FREQ=MONTHLY;BYDAY=MO,TU;BYSETPOS=-1;COUNT=10", or a set of
+// content lines containing an "RRULE", and optionally "DTSTART" and
+// "EXDATE" properties.
+// Supported rule parts are FREQ (YEARLY to MINUTELY), INTERVAL, COUNT,
+// UNTIL, BYDAY, BYMONTHDAY, BYMONTH, BYSETPOS, BYHOUR, BYMINUTE and WKST.
+// If no DTSTART is given, the start time of the Trigger is used.
+// If the provided string cannot be parsed, the function will panic with
+// the error.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) RRule(str string) *Trigger {
+	r, err := parseRRule(str)
+	if err != nil {
+		panic(err)
+	}
+	t.interval = 0
+	t.rule = r
+	return t
+}
+
+// parseRRule parses a recurrence rule with optional DTSTART and EXDATE
+// content lines.
+func parseRRule(spec string) (*rrule, error) {
+	r := &rrule{interval: 1, wkst: time.Monday}
+	rule, pos := "", -1
+	fields, offsets := cronTokens(spec)
+	for i, line := range fields {
+		name, value, offset := "", line, offsets[i]
+		if i := strings.Index(line, ":"); i >= 0 {
+			name, value, offset = line[:i], line[i+1:], offset+i+1
+		}
+		property := strings.ToUpper(strings.SplitN(name, ";", 2)[0])
+		switch property {
+		case "", "RRULE":
+			if rule != "" {
+				return nil, &ParseError{spec, offsets[i], "a single RRULE", ErrInvalidRRule}
+			}
+			rule, pos = value, offset
+		case "DTSTART", "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				tm, err := parseICalTime(name, v)
+				if err != nil {
+					return nil, &ParseError{spec, offset, "a known TZID and a date such as 19970902T090000", ErrInvalidRRule}
+				}
+				if property == "DTSTART" {
+					r.dtstart = tm
+				} else {
+					r.exdates = append(r.exdates, tm)
+				}
+				offset += len(v) + 1
+			}
+		default:
+			return nil, &ParseError{spec, offsets[i], "an RRULE, DTSTART or EXDATE property", ErrInvalidRRule}
+		}
+	}
+	if rule == "" {
+		return nil, &ParseError{spec, len(spec), "an RRULE property", ErrInvalidRRule}
+	}
+	if err := r.parse(spec, pos, rule); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parse parses the rule parts of a recurrence rule found at pos in spec.
+func (r *rrule) parse(spec string, pos int, rule string) error {
+	freq := false
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return &ParseError{spec, pos, "a rule part such as FREQ=DAILY", ErrInvalidRRule}
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		expected := ""
+		var err error
+		switch key {
+		case "FREQ":
+			f, ok := rruleFreqs[value]
+			if !ok {
+				expected = "one of YEARLY, MONTHLY, WEEKLY, DAILY, HOURLY or MINUTELY"
+			}
+			r.freq, freq = f, true
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval < 1 {
+				expected = "a positive interval"
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err != nil || r.count < 1 {
+				expected = "a positive count"
+			}
+		case "UNTIL":
+			if r.until, err = parseICalTime("UNTIL", value); err != nil {
+				expected = "a date such as 19971224T000000Z"
+			}
+		case "BYDAY":
+			if r.byDay, err = parseRRuleDays(value); err != nil {
+				expected = "a list of weekdays such as MO,-1FR"
+			}
+		case "BYMONTHDAY":
+			if r.byMonthDay, err = parseRRuleInts(value, -31, 31); err != nil {
+				expected = "a list of days of the month (1 to 31 or -31 to -1)"
+			}
+		case "BYMONTH":
+			if r.byMonth, err = parseRRuleInts(value, 1, 12); err != nil {
+				expected = "a list of months (1 to 12)"
+			}
+		case "BYSETPOS":
+			if r.bySetPos, err = parseRRuleInts(value, -366, 366); err != nil {
+				expected = "a list of positions (1 to 366 or -366 to -1)"
+			}
+		case "BYHOUR":
+			if r.byHour, err = parseRRuleInts(value, 0, 23); err != nil {
+				expected = "a list of hours (0 to 23)"
+			}
+		case "BYMINUTE":
+			if r.byMinute, err = parseRRuleInts(value, 0, 59); err != nil {
+				expected = "a list of minutes (0 to 59)"
+			}
+		case "WKST":
+			d, ok := rruleWeekdays[value]
+			if !ok {
+				expected = "a weekday such as MO"
+			}
+			r.wkst = d
+		default:
+			return &ParseError{spec, pos, "one of FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, BYSETPOS, BYHOUR, BYMINUTE or WKST", ErrInvalidRRule}
+		}
+		if expected != "" {
+			return &ParseError{spec, pos + len(kv[0]) + 1, expected, ErrInvalidRRule}
+		}
+		pos += len(part) + 1
+	}
+	if !freq {
+		return &ParseError{spec, pos - 1, "a FREQ rule part", ErrInvalidRRule}
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return &ParseError{spec, pos - 1, "either COUNT or UNTIL, not both", ErrInvalidRRule}
+	}
+	return nil
+}
+
+// parseICalTime parses an iCalendar DATE or DATE-TIME value. The name holds
+// the property name and parameters, used to look up the TZID parameter.
+func parseICalTime(name, value string) (time.Time, error) {
+	loc := time.Local
+	for _, param := range strings.Split(name, ";")[1:] {
+		if kv := strings.SplitN(param, "=", 2); len(kv) == 2 && strings.EqualFold(kv[0], "TZID") {
+			l, err := time.LoadLocation(kv[1])
+			if err != nil {
+				return time.Time{}, fmt.Errorf("schedule: unknown TZID %q", kv[1])
+			}
+			loc = l
+		}
+	}
+	layout := "20060102T150405"
+	switch {
+	case len(value) == 8:
+		layout = "20060102"
+	case strings.HasSuffix(value, "Z"):
+		layout, loc = "20060102T150405Z", time.UTC
+	}
+	tm, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("schedule: invalid date %q", value)
+	}
+	return tm, nil
+}
+
+// parseRRuleDays parses a BYDAY value list.
+func parseRRuleDays(value string) ([]rruleDay, error) {
+	var days []rruleDay
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", v)
+		}
+		d, ok := rruleWeekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", v)
+		}
+		day := rruleDay{day: d}
+		if n := v[:len(v)-2]; n != "" {
+			i, err := strconv.Atoi(n)
+			if err != nil || i == 0 || i < -53 || i > 53 {
+				return nil, fmt.Errorf("invalid weekday %q", v)
+			}
+			day.n = i
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseRRuleInts parses a list of integers within [min, max]. If negative
+// values are allowed, 0 is not.
+func parseRRuleInts(value string, min, max int) ([]int, error) {
+	var ints []int
+	for _, v := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < min || i > max || (i == 0 && min < 0) {
+			return nil, fmt.Errorf("invalid value %q", v)
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
+
+func (r *rrule) next(start, after time.Time) time.Time {
+	dtstart := r.dtstart
+	if dtstart.IsZero() {
+		dtstart = start.Truncate(time.Second)
+	}
+	count := 0
+	for i := r.skip(dtstart, after); i < maxRRulePeriods; i++ {
+		candidates, begin := r.candidates(dtstart, i*r.interval)
+		if !r.until.IsZero() && begin.After(r.until) {
+			break
+		}
+		for _, c := range candidates {
+			if c.Before(dtstart) {
+				continue
+			}
+			if !r.until.IsZero() && c.After(r.until) {
+				return time.Time{}
+			}
+			count++
+			if r.count > 0 && count > r.count {
+				return time.Time{}
+			}
+			if c.After(after) && !r.excluded(c) {
+				return c
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// skip returns the index of the first period which may hold an occurrence
+// after the given time. Periods can only be skipped if the rule has no COUNT.
+func (r *rrule) skip(dtstart, after time.Time) int {
+	if r.count > 0 || !after.After(dtstart) {
+		return 0
+	}
+	n := 0
+	switch r.freq {
+	case freqYearly:
+		n = after.Year() - dtstart.Year()
+	case freqMonthly:
+		n = (after.Year()-dtstart.Year())*12 + int(after.Month()-dtstart.Month())
+	case freqWeekly:
+		n = civilDays(dtstart, after) / 7
+	case freqDaily:
+		n = civilDays(dtstart, after)
+	case freqHourly:
+		n = int(after.Sub(dtstart) / time.Hour)
+	case freqMinutely:
+		n = int(after.Sub(dtstart) / time.Minute)
+	}
+	if i := n/r.interval - 1; i > 0 {
+		return i
+	}
+	return 0
+}
+
+// candidates returns the sorted occurrences within the period k periods
+// after the one containing dtstart, along with the start of the period.
+func (r *rrule) candidates(dtstart time.Time, k int) ([]time.Time, time.Time) {
+	y, m, d := dtstart.Date()
+	loc := dtstart.Location()
+	var days []time.Time
+	var begin time.Time
+	switch r.freq {
+	case freqYearly:
+		begin = time.Date(y+k, 1, 1, 0, 0, 0, 0, loc)
+		days = r.filterDays(dtstart, begin, begin.AddDate(1, 0, 0))
+	case freqMonthly:
+		begin = time.Date(y, m+time.Month(k), 1, 0, 0, 0, 0, loc)
+		days = r.filterDays(dtstart, begin, begin.AddDate(0, 1, 0))
+	case freqWeekly:
+		offset := (int(dtstart.Weekday()) - int(r.wkst) + 7) % 7
+		begin = time.Date(y, m, d-offset+7*k, 0, 0, 0, 0, loc)
+		days = r.filterDays(dtstart, begin, begin.AddDate(0, 0, 7))
+	case freqDaily:
+		begin = time.Date(y, m, d+k, 0, 0, 0, 0, loc)
+		days = r.filterDays(dtstart, begin, begin.AddDate(0, 0, 1))
+	case freqHourly:
+		begin = time.Date(y, m, d, dtstart.Hour()+k, 0, 0, 0, loc)
+		if r.matchesDay(dtstart, begin) && contains(r.byHour, begin.Hour()) {
+			days = []time.Time{begin}
+		}
+	case freqMinutely:
+		begin = time.Date(y, m, d, dtstart.Hour(), dtstart.Minute()+k, 0, 0, loc)
+		if r.matchesDay(dtstart, begin) && contains(r.byHour, begin.Hour()) &&
+			contains(r.byMinute, begin.Minute()) {
+			days = []time.Time{begin}
+		}
+	}
+	var candidates []time.Time
+	for _, day := range days {
+		candidates = append(candidates, r.times(dtstart, day)...)
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].Before(candidates[b])
+	})
+	return r.setPos(candidates), begin
+}
+
+// filterDays returns the days in [begin, end) matching the rule.
+func (r *rrule) filterDays(dtstart, begin, end time.Time) []time.Time {
+	var days []time.Time
+	for day := begin; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if r.matchesDay(dtstart, day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// matchesDay returns whether the date of tm matches the BYMONTH, BYMONTHDAY
+// and BYDAY parts of the rule, expanding from dtstart where the rule has
+// none of them.
+func (r *rrule) matchesDay(dtstart, tm time.Time) bool {
+	if len(r.byMonth) > 0 && !contains(r.byMonth, int(tm.Month())) {
+		return false
+	}
+	if len(r.byMonthDay) > 0 {
+		days := daysIn(tm.Year(), tm.Month())
+		match := false
+		for _, md := range r.byMonthDay {
+			if md == tm.Day() || days+md+1 == tm.Day() {
+				match = true
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	if len(r.byDay) > 0 {
+		return r.matchesWeekday(tm)
+	}
+	if len(r.byMonthDay) > 0 {
+		return true
+	}
+	switch r.freq {
+	case freqYearly:
+		return tm.Day() == dtstart.Day() &&
+			(len(r.byMonth) > 0 || tm.Month() == dtstart.Month())
+	case freqMonthly:
+		return tm.Day() == dtstart.Day()
+	case freqWeekly:
+		return tm.Weekday() == dtstart.Weekday()
+	}
+	return true
+}
+
+// matchesWeekday returns whether tm matches the BYDAY part of the rule.
+// Ordinal weekdays are counted within the month for MONTHLY rules and
+// YEARLY rules with BYMONTH, and within the year for other YEARLY rules.
+func (r *rrule) matchesWeekday(tm time.Time) bool {
+	for _, d := range r.byDay {
+		if tm.Weekday() != d.day {
+			continue
+		}
+		if d.n == 0 || (r.freq != freqMonthly && r.freq != freqYearly) {
+			return true
+		}
+		day, days := tm.Day(), daysIn(tm.Year(), tm.Month())
+		if r.freq == freqYearly && len(r.byMonth) == 0 {
+			day = tm.YearDay()
+			days = time.Date(tm.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		}
+		if d.n > 0 && (day-1)/7 == d.n-1 {
+			return true
+		}
+		if d.n < 0 && (days-day)/7 == -d.n-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// times returns the occurrences on the date of day, expanding BYHOUR and
+// BYMINUTE for daily and longer periods.
+func (r *rrule) times(dtstart, day time.Time) []time.Time {
+	y, m, d := day.Date()
+	if r.freq == freqHourly || r.freq == freqMinutely {
+		minutes := []int{day.Minute()}
+		if r.freq == freqHourly {
+			minutes = orDefault(r.byMinute, dtstart.Minute())
+		}
+		var times []time.Time
+		for _, minute := range minutes {
+			times = append(times, time.Date(y, m, d, day.Hour(), minute, dtstart.Second(), 0, day.Location()))
+		}
+		return times
+	}
+	var times []time.Time
+	for _, hour := range orDefault(r.byHour, dtstart.Hour()) {
+		for _, minute := range orDefault(r.byMinute, dtstart.Minute()) {
+			times = append(times, time.Date(y, m, d, hour, minute, dtstart.Second(), 0, day.Location()))
+		}
+	}
+	return times
+}
+
+// setPos applies the BYSETPOS part of the rule to the sorted candidates.
+func (r *rrule) setPos(candidates []time.Time) []time.Time {
+	if len(r.bySetPos) == 0 {
+		return candidates
+	}
+	var selected []time.Time
+	for i, c := range candidates {
+		for _, pos := range r.bySetPos {
+			if pos == i+1 || pos == i-len(candidates) {
+				selected = append(selected, c)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// excluded returns whether tm is one of the EXDATE values of the rule.
+func (r *rrule) excluded(tm time.Time) bool {
+	for _, ex := range r.exdates {
+		if ex.Equal(tm) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns whether v is in list. An empty list contains everything.
+func contains(list []int, v int) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, i := range list {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// orDefault returns list, or a list holding only def if list is empty.
+func orDefault(list []int, def int) []int {
+	if len(list) == 0 {
+		return []int{def}
+	}
+	return list
+}
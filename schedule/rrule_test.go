@@ -0,0 +1,249 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+// occurrences returns the first n occurrences of the rule, starting at its
+// DTSTART.
+func occurrences(t *testing.T, rule string, n int) []time.Time {
+	r, err := parseRRule(rule)
+	if err != nil {
+		t.Fatalf("Could not parse rule %q: %v", rule, err)
+	}
+	var times []time.Time
+	after := r.dtstart.Add(-time.Second)
+	for len(times) < n {
+		next := r.next(time.Now(), after)
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+		after = next
+	}
+	return times
+}
+
+func TestRRule_RFC5545(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Could not load time zone: %v", err)
+	}
+	date := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+	tests := []struct {
+		name string
+		rule string
+		n    int
+		want []time.Time
+	}{
+		{
+			"daily for 10 occurrences",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=DAILY;COUNT=10",
+			20,
+			[]time.Time{
+				date(1997, 9, 2, 9, 0), date(1997, 9, 3, 9, 0), date(1997, 9, 4, 9, 0),
+				date(1997, 9, 5, 9, 0), date(1997, 9, 6, 9, 0), date(1997, 9, 7, 9, 0),
+				date(1997, 9, 8, 9, 0), date(1997, 9, 9, 9, 0), date(1997, 9, 10, 9, 0),
+				date(1997, 9, 11, 9, 0),
+			},
+		},
+		{
+			"every 10 days, 5 occurrences",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=DAILY;INTERVAL=10;COUNT=5",
+			20,
+			[]time.Time{
+				date(1997, 9, 2, 9, 0), date(1997, 9, 12, 9, 0), date(1997, 9, 22, 9, 0),
+				date(1997, 10, 2, 9, 0), date(1997, 10, 12, 9, 0),
+			},
+		},
+		{
+			"weekly for 10 occurrences",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=WEEKLY;COUNT=10",
+			20,
+			[]time.Time{
+				date(1997, 9, 2, 9, 0), date(1997, 9, 9, 9, 0), date(1997, 9, 16, 9, 0),
+				date(1997, 9, 23, 9, 0), date(1997, 9, 30, 9, 0), date(1997, 10, 7, 9, 0),
+				date(1997, 10, 14, 9, 0), date(1997, 10, 21, 9, 0), date(1997, 10, 28, 9, 0),
+				date(1997, 11, 4, 9, 0),
+			},
+		},
+		{
+			"weekly on tuesday and thursday for five weeks",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			20,
+			[]time.Time{
+				date(1997, 9, 2, 9, 0), date(1997, 9, 4, 9, 0), date(1997, 9, 9, 9, 0),
+				date(1997, 9, 11, 9, 0), date(1997, 9, 16, 9, 0), date(1997, 9, 18, 9, 0),
+				date(1997, 9, 23, 9, 0), date(1997, 9, 25, 9, 0), date(1997, 9, 30, 9, 0),
+				date(1997, 10, 2, 9, 0),
+			},
+		},
+		{
+			"monthly on the first friday for 10 occurrences",
+			"DTSTART;TZID=America/New_York:19970905T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			20,
+			[]time.Time{
+				date(1997, 9, 5, 9, 0), date(1997, 10, 3, 9, 0), date(1997, 11, 7, 9, 0),
+				date(1997, 12, 5, 9, 0), date(1998, 1, 2, 9, 0), date(1998, 2, 6, 9, 0),
+				date(1998, 3, 6, 9, 0), date(1998, 4, 3, 9, 0), date(1998, 5, 1, 9, 0),
+				date(1998, 6, 5, 9, 0),
+			},
+		},
+		{
+			"monthly on the second-to-last monday for 6 months",
+			"DTSTART;TZID=America/New_York:19970922T090000\nRRULE:FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			20,
+			[]time.Time{
+				date(1997, 9, 22, 9, 0), date(1997, 10, 20, 9, 0), date(1997, 11, 17, 9, 0),
+				date(1997, 12, 22, 9, 0), date(1998, 1, 19, 9, 0), date(1998, 2, 16, 9, 0),
+			},
+		},
+		{
+			"monthly on the third-to-the-last day of the month",
+			"DTSTART;TZID=America/New_York:19970928T090000\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-3",
+			6,
+			[]time.Time{
+				date(1997, 9, 28, 9, 0), date(1997, 10, 29, 9, 0), date(1997, 11, 28, 9, 0),
+				date(1997, 12, 29, 9, 0), date(1998, 1, 29, 9, 0), date(1998, 2, 26, 9, 0),
+			},
+		},
+		{
+			"monthly on the 2nd and 15th of the month for 10 occurrences",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15",
+			20,
+			[]time.Time{
+				date(1997, 9, 2, 9, 0), date(1997, 9, 15, 9, 0), date(1997, 10, 2, 9, 0),
+				date(1997, 10, 15, 9, 0), date(1997, 11, 2, 9, 0), date(1997, 11, 15, 9, 0),
+				date(1997, 12, 2, 9, 0), date(1997, 12, 15, 9, 0), date(1998, 1, 2, 9, 0),
+				date(1998, 1, 15, 9, 0),
+			},
+		},
+		{
+			"yearly in june and july for 10 occurrences",
+			"DTSTART;TZID=America/New_York:19970610T090000\nRRULE:FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+			20,
+			[]time.Time{
+				date(1997, 6, 10, 9, 0), date(1997, 7, 10, 9, 0), date(1998, 6, 10, 9, 0),
+				date(1998, 7, 10, 9, 0), date(1999, 6, 10, 9, 0), date(1999, 7, 10, 9, 0),
+				date(2000, 6, 10, 9, 0), date(2000, 7, 10, 9, 0), date(2001, 6, 10, 9, 0),
+				date(2001, 7, 10, 9, 0),
+			},
+		},
+		{
+			"every 20th monday of the year",
+			"DTSTART;TZID=America/New_York:19970519T090000\nRRULE:FREQ=YEARLY;BYDAY=20MO",
+			3,
+			[]time.Time{
+				date(1997, 5, 19, 9, 0), date(1998, 5, 18, 9, 0), date(1999, 5, 17, 9, 0),
+			},
+		},
+		{
+			"every thursday in march",
+			"DTSTART;TZID=America/New_York:19970313T090000\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=TH",
+			7,
+			[]time.Time{
+				date(1997, 3, 13, 9, 0), date(1997, 3, 20, 9, 0), date(1997, 3, 27, 9, 0),
+				date(1998, 3, 5, 9, 0), date(1998, 3, 12, 9, 0), date(1998, 3, 19, 9, 0),
+				date(1998, 3, 26, 9, 0),
+			},
+		},
+		{
+			"every friday the 13th, excluding dtstart",
+			"DTSTART;TZID=America/New_York:19970902T090000\nEXDATE;TZID=America/New_York:19970902T090000\nRRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			5,
+			[]time.Time{
+				date(1998, 2, 13, 9, 0), date(1998, 3, 13, 9, 0), date(1998, 11, 13, 9, 0),
+				date(1999, 8, 13, 9, 0), date(2000, 10, 13, 9, 0),
+			},
+		},
+		{
+			"the third instance of tuesday, wednesday or thursday for 3 months",
+			"DTSTART;TZID=America/New_York:19970904T090000\nRRULE:FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			20,
+			[]time.Time{
+				date(1997, 9, 4, 9, 0), date(1997, 10, 7, 9, 0), date(1997, 11, 6, 9, 0),
+			},
+		},
+		{
+			"the last work day of the month",
+			"DTSTART;TZID=America/New_York:19970929T090000\nRRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			6,
+			[]time.Time{
+				date(1997, 9, 30, 9, 0), date(1997, 10, 31, 9, 0), date(1997, 11, 28, 9, 0),
+				date(1997, 12, 31, 9, 0), date(1998, 1, 30, 9, 0), date(1998, 2, 27, 9, 0),
+			},
+		},
+		{
+			"every 20 minutes from 9:00 to 16:40 every day",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=DAILY;BYHOUR=9,10,11,12,13,14,15,16;BYMINUTE=0,20,40",
+			26,
+			nil,
+		},
+		{
+			"every 20 minutes from 9:00 to 16:40 every day, minutely",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10,11,12,13,14,15,16",
+			26,
+			nil,
+		},
+	}
+	for _, test := range tests {
+		got := occurrences(t, test.rule, test.n)
+		want := test.want
+		if want == nil {
+			for _, day := range []int{2, 3} {
+				for hour := 9; hour <= 16; hour++ {
+					for _, min := range []int{0, 20, 40} {
+						want = append(want, date(1997, 9, day, hour, min))
+					}
+				}
+			}
+			want = want[:test.n]
+		}
+		if len(got) != len(want) {
+			t.Errorf("Occurrences for %s did not match. Got %d, expected %d", test.name, len(got), len(want))
+			continue
+		}
+		for i := range want {
+			if !got[i].Equal(want[i]) {
+				t.Errorf("Occurrence %d for %s did not match. Got %v, expected %v", i, test.name, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestRRule_NextWithoutDTStart(t *testing.T) {
+	start := time.Date(2028, 1, 1, 12, 0, 0, 0, time.UTC)
+	trigger := NewTrigger().From(start).RRule("FREQ=DAILY;INTERVAL=2")
+	next := time.Date(2028, 1, 5, 12, 0, 0, 0, time.UTC)
+	if n := trigger.NextAfter(start.AddDate(0, 0, 3)); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+}
+
+func TestTrigger_RRulePanic(t *testing.T) {
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=SECONDLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20280101",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=DAILY;FOO=BAR",
+		"DTSTART;TZID=Nowhere/Nothing:20280101T090000\nRRULE:FREQ=DAILY",
+	}
+	for _, rule := range invalid {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Errorf("RRule did not panic with invalid rule %q", rule)
+				}
+			}()
+			NewTrigger().RRule(rule)
+		}()
+	}
+}
@@ -7,26 +7,42 @@ import (
 	"time"
 )
 
+// ErrJobNotFound is returned when a job with the given name is not present
+// in any queue of a Scheduler.
+var ErrJobNotFound = errors.New("schedule: job not found")
+
 // A Scheduler represents an active Queue runner.
 type Scheduler struct {
 	Queues  map[string]*Queue
 	errors  chan JobError
+	events  chan JobEvent
 	mutex   sync.RWMutex
 	results chan JobResult
 	running bool
+	slots   *slotPool
+	tagged  *selectorLimiters
 }
 
 // NewScheduler creates a new Scheduler with a single "default" queue.
-// By default, the Scheduler is initialized with a max results and error
-// buffer of 10. See MaxBufferedErrors and MaxBufferedResults.
+// By default, the Scheduler is initialized with a max results, error and
+// event buffer of 10. See MaxBufferedErrors, MaxBufferedEvents and
+// MaxBufferedResults.
 func NewScheduler() *Scheduler {
+	slots, tagged := newSlotPool(), &selectorLimiters{}
+	queue := NewQueue()
+	queue.name = "default"
+	queue.slots = slots
+	queue.tagged = tagged
 	return &Scheduler{
 		Queues: map[string]*Queue{
-			"default": NewQueue(),
+			"default": queue,
 		},
 		errors:  make(chan JobError, 10),
+		events:  make(chan JobEvent, 10),
 		results: make(chan JobResult, 10),
 		running: false,
+		slots:   slots,
+		tagged:  tagged,
 	}
 }
 
@@ -49,6 +65,14 @@ func (s *Scheduler) Errors() chan JobError {
 	return s.errors
 }
 
+// Events returns the channel on which job state transitions are emitted.
+// If the buffer is full, new events are dropped.
+func (s *Scheduler) Events() chan JobEvent {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.events
+}
+
 // MaxBufferedErrors sets the buffer length of the job errors channel.
 // This method will also be called on all available queues.
 // Please note that this will not affect any channels added after.
@@ -70,6 +94,28 @@ func (s *Scheduler) MaxBufferedErrors(n int) {
 	s.mutex.RUnlock()
 }
 
+// MaxBufferedEvents sets the buffer length of the job events channel.
+// This method will also be called on all available queues.
+// Please note that this will not affect any channels added after.
+func (s *Scheduler) MaxBufferedEvents(n int) {
+	events := make(chan JobEvent, n)
+	s.mutex.Lock()
+	close(s.events)
+	for event := range s.events {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+	s.events = events
+	s.mutex.Unlock()
+	s.mutex.RLock()
+	for _, queue := range s.Queues {
+		queue.MaxBufferedEvents(n)
+	}
+	s.mutex.RUnlock()
+}
+
 // MaxBufferedResults sets the buffer length of the job results channel.
 // This method will also be called on all available queues.
 // Please note that this will not affect any channels added after.
@@ -98,6 +144,23 @@ func (s *Scheduler) Results() chan JobResult {
 	return s.results
 }
 
+// RunNow requests a run of every job with the given name in the queues of
+// this Scheduler, regardless of their schedules. See Job.Trigger.
+// If no job has the given name, ErrJobNotFound is returned.
+func (s *Scheduler) RunNow(name string) error {
+	found := false
+	for _, job := range s.jobs() {
+		if job.Name == name {
+			job.Trigger()
+			found = true
+		}
+	}
+	if !found {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
 // Running returns whether this Scheduler is currently running.
 func (s *Scheduler) Running() bool {
 	s.mutex.RLock()
@@ -122,7 +185,7 @@ func (s *Scheduler) Start() error {
 					queue.Run()
 				}(queue)
 				go func(queue *Queue) {
-					for len(queue.Errors()) > 0 || len(queue.Results()) > 0 {
+					for len(queue.Errors()) > 0 || len(queue.Events()) > 0 || len(queue.Results()) > 0 {
 						select {
 						case err := <-queue.Errors():
 							s.mutex.Lock()
@@ -130,6 +193,13 @@ func (s *Scheduler) Start() error {
 							case s.errors <- err:
 							}
 							s.mutex.Unlock()
+						case event := <-queue.Events():
+							s.mutex.RLock()
+							select {
+							case s.events <- event:
+							default:
+							}
+							s.mutex.RUnlock()
 						case res := <-queue.Results():
 							s.mutex.Lock()
 							select {
@@ -154,12 +224,34 @@ func (s *Scheduler) Stop() {
 	s.running = false
 }
 
+// jobs returns the jobs in all queues of this Scheduler.
+func (s *Scheduler) jobs() []*Job {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var jobs []*Job
+	for _, queue := range s.Queues {
+		queue.mutex.RLock()
+		jobs = append(jobs, queue.Jobs...)
+		queue.mutex.RUnlock()
+	}
+	return jobs
+}
+
 // Queue adds a new Queue to this Scheduler.
 // If a Queue is already present with the same name, it will be overwritten.
-// Please note that any calls to MaxBufferedErrors or MaxBufferedResults does
-// not affect any Queues added later. You will need to call these manually.
+// Please note that any calls to MaxBufferedErrors, MaxBufferedEvents or
+// MaxBufferedResults does not affect any Queues added later. You will need to
+// call these manually.
 func (s *Scheduler) Queue(name string, queue *Queue) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
+	if old, ok := s.Queues[name]; ok && old != queue {
+		s.slots.forget(old)
+	}
+	queue.mutex.Lock()
+	queue.name = name
+	queue.slots = s.slots
+	queue.tagged = s.tagged
+	queue.mutex.Unlock()
 	s.Queues[name] = queue
 }
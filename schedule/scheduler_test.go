@@ -50,6 +50,13 @@ func TestScheduler_Errors(t *testing.T) {
 	}
 }
 
+func TestScheduler_Events(t *testing.T) {
+	s := NewScheduler()
+	if s.Events() == nil {
+		t.Error("Event buffer was nil.")
+	}
+}
+
 func TestScheduler_MaxBufferedErrors(t *testing.T) {
 	s := NewScheduler()
 	s.MaxBufferedErrors(50)
@@ -79,6 +86,23 @@ func TestScheduler_MaxBufferedErrorsCopy(t *testing.T) {
 	}
 }
 
+func TestScheduler_MaxBufferedEvents(t *testing.T) {
+	s := NewScheduler()
+	s.MaxBufferedEvents(50)
+	if cap(s.events) != 50 {
+		t.Errorf(
+			"Event buffer for Scheduler did not match. Got %d, expected 50.",
+			cap(s.events),
+		)
+	}
+	if cap(s.Queues["default"].events) != 50 {
+		t.Errorf(
+			"Event buffer for default queue did not match. Got %d, expected 50.",
+			cap(s.Queues["default"].events),
+		)
+	}
+}
+
 func TestScheduler_MaxBufferedResults(t *testing.T) {
 	s := NewScheduler()
 	s.MaxBufferedResults(50)
@@ -192,3 +216,37 @@ func TestScheduler_Queue(t *testing.T) {
 		t.Error("Could not find test queue in Scheduler.")
 	}
 }
+
+func TestScheduler_RunNow(t *testing.T) {
+	s := NewScheduler()
+	j, err := NewJob("test", func() string { return "test" })
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	j.Schedule().Every("1h")
+	s.Add(j)
+	if err := s.RunNow("test"); err != nil {
+		t.Fatalf("Scheduler errored on RunNow: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Errorf("Scheduler errored on Start: %v", err)
+	}
+	defer s.Stop()
+	time.Sleep(200 * time.Millisecond)
+	if len(s.results) != 1 {
+		t.Errorf(
+			"Results in buffer did not match. Got %d, expected 1",
+			len(s.results),
+		)
+	}
+	if j.RunCount() != 1 {
+		t.Errorf("Run count did not match. Got %d, expected 1", j.RunCount())
+	}
+}
+
+func TestScheduler_RunNowNotFound(t *testing.T) {
+	s := NewScheduler()
+	if err := s.RunNow("missing"); err != ErrJobNotFound {
+		t.Errorf("Error did not match. Got %v, expected %v", err, ErrJobNotFound)
+	}
+}
@@ -0,0 +1,239 @@
+package schedule
+
+import "strings"
+
+// selectorOp represents the operator of a selector requirement.
+type selectorOp int
+
+const (
+	opEquals selectorOp = iota
+	opNotEquals
+	opIn
+	opNotIn
+	opExists
+	opNotExists
+)
+
+// requirement represents a single condition of a Selector on a label.
+type requirement struct {
+	key    string
+	op     selectorOp
+	values []string
+}
+
+// matches returns whether the labels satisfy the requirement.
+func (r requirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.key]
+	switch r.op {
+	case opEquals:
+		return ok && value == r.values[0]
+	case opNotEquals:
+		return !ok || value != r.values[0]
+	case opIn:
+		return ok && containsString(r.values, value)
+	case opNotIn:
+		return !ok || !containsString(r.values, value)
+	case opExists:
+		return ok
+	case opNotExists:
+		return !ok
+	}
+	return false
+}
+
+// A Selector represents a label selector matching jobs by their labels.
+// See ParseSelector and Job.Tags.
+type Selector struct {
+	spec         string
+	requirements []requirement
+}
+
+// ParseSelector creates a new Selector from a comma separated list of
+// requirements, all of which need to be satisfied, e.g.
+//
+//	env=prod,team in (billing,ops)
+//
+// The following requirements are supported:
+//
+//	key=value, key==value    the label is set to value
+//	key!=value               the label is not set to value, or not set
+//	key in (a,b)             the label is set to one of the values
+//	key notin (a,b)          the label is not set to any of the values
+//	key                      the label is set
+//	!key                     the label is not set
+//
+// An empty selector matches every job.
+// If the selector could not be parsed, a *ParseError wrapping
+// ErrInvalidSelector is returned.
+func ParseSelector(str string) (*Selector, error) {
+	p := newSelectorParser(str)
+	s := &Selector{spec: str}
+	if len(p.tokens) == 0 {
+		return s, nil
+	}
+	for {
+		r, err := p.requirement()
+		if err != nil {
+			return nil, err
+		}
+		s.requirements = append(s.requirements, r)
+		if p.peek() == "" {
+			return s, nil
+		}
+		if !p.accept(",") {
+			return nil, p.fail("\",\"")
+		}
+	}
+}
+
+// Matches returns whether the labels satisfy every requirement of the
+// selector.
+func (s *Selector) Matches(labels map[string]string) bool {
+	for _, r := range s.requirements {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector as it was parsed.
+func (s *Selector) String() string {
+	return s.spec
+}
+
+// selectorParser holds the state of ParseSelector.
+type selectorParser struct {
+	spec    string
+	tokens  []string
+	offsets []int
+	i       int
+}
+
+// newSelectorParser splits the selector into label names, values and
+// operators.
+func newSelectorParser(spec string) *selectorParser {
+	p := &selectorParser{spec: spec}
+	for i := 0; i < len(spec); {
+		switch c := spec[i]; {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case strings.HasPrefix(spec[i:], "==") || strings.HasPrefix(spec[i:], "!="):
+			p.push(spec[i:i+2], i)
+			i += 2
+			continue
+		case strings.IndexByte("=!(),", c) >= 0:
+			p.push(spec[i:i+1], i)
+			i++
+			continue
+		}
+		start := i
+		for i < len(spec) && strings.IndexByte(" \t=!(),", spec[i]) < 0 {
+			i++
+		}
+		p.push(spec[start:i], start)
+	}
+	return p
+}
+
+// push appends a token found at the given offset.
+func (p *selectorParser) push(token string, offset int) {
+	p.tokens = append(p.tokens, token)
+	p.offsets = append(p.offsets, offset)
+}
+
+// requirement parses a single requirement.
+func (p *selectorParser) requirement() (requirement, error) {
+	if p.accept("!") {
+		key, err := p.name("a label name")
+		return requirement{key: key, op: opNotExists}, err
+	}
+	key, err := p.name("a label name or \"!\"")
+	if err != nil {
+		return requirement{}, err
+	}
+	r := requirement{key: key, op: opExists}
+	switch {
+	case p.accept("=", "=="):
+		r.op = opEquals
+	case p.accept("!="):
+		r.op = opNotEquals
+	case p.accept("in"):
+		r.op = opIn
+	case p.accept("notin"):
+		r.op = opNotIn
+	default:
+		return r, nil
+	}
+	if r.op == opEquals || r.op == opNotEquals {
+		value, err := p.name("a label value")
+		r.values = []string{value}
+		return r, err
+	}
+	if !p.accept("(") {
+		return r, p.fail("\"(\"")
+	}
+	for {
+		value, err := p.name("a label value")
+		if err != nil {
+			return r, err
+		}
+		r.values = append(r.values, value)
+		if p.accept(")") {
+			return r, nil
+		}
+		if !p.accept(",") {
+			return r, p.fail("\",\" or \")\"")
+		}
+	}
+}
+
+// name parses a label name or value.
+func (p *selectorParser) name(expected string) (string, error) {
+	token := p.peek()
+	if token == "" || strings.IndexByte("=!(),", token[0]) >= 0 {
+		return "", p.fail(expected)
+	}
+	p.i++
+	return token, nil
+}
+
+// peek returns the current token, or an empty string at the end of the
+// selector.
+func (p *selectorParser) peek() string {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i]
+	}
+	return ""
+}
+
+// accept consumes the current token if it is one of the given tokens.
+func (p *selectorParser) accept(tokens ...string) bool {
+	for _, t := range tokens {
+		if p.peek() == t {
+			p.i++
+			return true
+		}
+	}
+	return false
+}
+
+// fail returns a *ParseError for the current token.
+func (p *selectorParser) fail(expected string) error {
+	pos := len(p.spec)
+	if p.i < len(p.offsets) {
+		pos = p.offsets[p.i]
+	}
+	return &ParseError{p.spec, pos, expected, ErrInvalidSelector}
+}
+
+// containsString returns whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
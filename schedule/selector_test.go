@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "billing", "tier": "batch"}
+	for _, test := range []struct {
+		selector string
+		expected bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env==prod", true},
+		{"env=dev", false},
+		{"env!=dev", true},
+		{"env!=prod", false},
+		{"region!=eu", true},
+		{"team in (billing,ops)", true},
+		{"team in (ops)", false},
+		{"team notin (ops, search)", true},
+		{"team notin (billing)", false},
+		{"region notin (eu)", true},
+		{"region in (eu)", false},
+		{"tier", true},
+		{"region", false},
+		{"!region", true},
+		{"!tier", false},
+		{"env=prod,team in (billing,ops)", true},
+		{"env=prod, team in (billing,ops), !region", true},
+		{"env=prod,team=ops", false},
+	} {
+		sel, err := ParseSelector(test.selector)
+		if err != nil {
+			t.Errorf("Could not parse %q: %v", test.selector, err)
+			continue
+		}
+		if actual := sel.Matches(labels); actual != test.expected {
+			t.Errorf("Match for %q did not match. Got %v, expected %v", test.selector, actual, test.expected)
+		}
+		if sel.String() != test.selector {
+			t.Errorf("String did not match. Got %q, expected %q", sel.String(), test.selector)
+		}
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, test := range []struct {
+		selector string
+		pos      int
+	}{
+		{"=prod", 0},
+		{"env=", 4},
+		{"env=prod,", 9},
+		{"env prod", 4},
+		{"team in billing", 8},
+		{"team in (billing", 16},
+		{"team in (billing ops)", 17},
+		{"team in ()", 9},
+		{"!", 1},
+		{"env=(prod)", 4},
+	} {
+		_, err := ParseSelector(test.selector)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Error for %q was not a *ParseError. Got %v", test.selector, err)
+			continue
+		}
+		if !errors.Is(err, ErrInvalidSelector) || perr.Pos != test.pos {
+			t.Errorf("Error for %q did not match. Got %v at %d, expected %v at %d", test.selector, perr.Err, perr.Pos, ErrInvalidSelector, test.pos)
+		}
+	}
+}
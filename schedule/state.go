@@ -0,0 +1,83 @@
+package schedule
+
+import "time"
+
+// A JobState represents the lifecycle state of a job.
+type JobState int
+
+const (
+	// StateScheduled indicates that the job is waiting for its next run.
+	StateScheduled JobState = iota
+	// StateRunning indicates that the job function is currently executing.
+	StateRunning
+	// StatePaused indicates that the job has been paused and will not run
+	// until resumed.
+	StatePaused
+	// StateCompleted indicates that the job will not run again.
+	StateCompleted
+	// StateFailed indicates that the last run of the job failed.
+	// The job will still run at its next scheduled time.
+	StateFailed
+	// StateDisabled indicates that the job has been disabled and will not
+	// run until enabled.
+	StateDisabled
+)
+
+// String returns the name of the state.
+func (s JobState) String() string {
+	switch s {
+	case StateScheduled:
+		return "scheduled"
+	case StateRunning:
+		return "running"
+	case StatePaused:
+		return "paused"
+	case StateCompleted:
+		return "completed"
+	case StateFailed:
+		return "failed"
+	case StateDisabled:
+		return "disabled"
+	}
+	return "unknown"
+}
+
+// An EventType represents the kind of a JobEvent.
+type EventType int
+
+const (
+	// EventTransition indicates that the state of the job changed.
+	EventTransition EventType = iota
+	// EventDelayed indicates that a run of the job is waiting for a
+	// RateLimiter to have budget again.
+	EventDelayed
+	// EventSkipped indicates that a run of the job was dropped because a
+	// RateLimiter had no budget left.
+	EventSkipped
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventTransition:
+		return "transition"
+	case EventDelayed:
+		return "delayed"
+	case EventSkipped:
+		return "skipped"
+	}
+	return "unknown"
+}
+
+// A JobEvent represents a state transition of a job, or a run of a job
+// throttled by a RateLimiter.
+// The name of the job is stored in .Name, the previous and new state in
+// .From and .To, the time of the event in .Time and its kind in .Type.
+// Throttling events leave the state of the job unchanged.
+type JobEvent struct {
+	Name string
+	From JobState
+	To   JobState
+	Time time.Time
+	Type EventType
+}
@@ -0,0 +1,20 @@
+package schedule
+
+import "testing"
+
+func TestJobState_String(t *testing.T) {
+	states := map[JobState]string{
+		StateScheduled: "scheduled",
+		StateRunning:   "running",
+		StatePaused:    "paused",
+		StateCompleted: "completed",
+		StateFailed:    "failed",
+		StateDisabled:  "disabled",
+		JobState(-1):   "unknown",
+	}
+	for state, name := range states {
+		if state.String() != name {
+			t.Errorf("State name did not match. Got %s, expected %s", state, name)
+		}
+	}
+}
@@ -1,12 +1,28 @@
 package schedule
 
-import "time"
+import (
+	"hash/fnv"
+	"time"
+)
+
+// maxExcludedRuns is the number of consecutive excluded times skipped when
+// looking for the next scheduled time before giving up.
+const maxExcludedRuns = 10000
 
 // A Trigger represents the time schedule for a job.
 type Trigger struct {
-	interval time.Duration
-	start    time.Time
-	limit    int64
+	interval   time.Duration
+	start      time.Time
+	limit      int64
+	successful bool
+	rule       recurrence
+	until      time.Time
+	excluded   Calendar
+	shift      bool
+	jitter     time.Duration
+	splay      time.Duration
+	seed       int64
+	delayed    bool
 }
 
 // NewTrigger creates a new Trigger.
@@ -19,6 +35,35 @@ func NewTrigger() *Trigger {
 	}
 }
 
+// After schedules a single run of the job d after the start time of the
+// Trigger. See From.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) After(d time.Duration) *Trigger {
+	t.interval = 0
+	t.rule = delay(d)
+	return t
+}
+
+// At schedules a single run of the job at the given time.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) At(tm time.Time) *Trigger {
+	return t.AtTimes(tm)
+}
+
+// AtTimes schedules a run of the job at each of the given times.
+// Any recurrence previously set on the Trigger is replaced.
+func (t *Trigger) AtTimes(ts ...time.Time) *Trigger {
+	t.interval = 0
+	t.rule = newTimeList(ts)
+	return t
+}
+
+// Between sets both the start time and the end time of the Trigger.
+// See From and Until.
+func (t *Trigger) Between(start, end time.Time) *Trigger {
+	return t.From(start).Until(end)
+}
+
 // Every sets the recurrence for the Trigger. The value passed needs to be
 // parsable by time.ParseDuration. E.g Trigger.Every("1m30s").
 // If the provided string cannot be parsed, the function will panic with
@@ -34,41 +79,207 @@ func (t *Trigger) Every(str string) *Trigger {
 		d = 0
 	}
 	t.interval = d
+	t.rule = nil
+	t.delayed = false
+	return t
+}
+
+// EveryAfterCompletion sets a fixed delay recurrence for the Trigger. The
+// value passed is parsed like Every, but the next run of the job is counted
+// from the time its previous run finished, rather than from the start time.
+// The first run is counted from the start time.
+func (t *Trigger) EveryAfterCompletion(str string) *Trigger {
+	t.Every(str)
+	t.delayed = true
+	return t
+}
+
+// Jitter delays every scheduled time of the Trigger by a random duration
+// between 0 and max. The delay is chosen separately for every scheduled time,
+// but stays the same for a given scheduled time. See Seed.
+// If 0 or a negative value is provided, the jitter is removed.
+func (t *Trigger) Jitter(max time.Duration) *Trigger {
+	if max < 0 {
+		max = 0
+	}
+	if t.seed == 0 {
+		t.seed = time.Now().UnixNano()
+	}
+	t.jitter = max
 	return t
 }
 
-// Limit sets the number of times a job is allowed to run before Next returns
-// a zeroed time.Time.
+// Limit sets the number of times a job is allowed to run before it is
+// completed. Every run is counted, whether it succeeded or not.
 // If 0 or a negative value is provided, the limit will be set to 0 (no limit).
 func (t *Trigger) Limit(n int64) *Trigger {
 	if n < 0 {
 		n = 0
 	}
 	t.limit = n
+	t.successful = false
+	return t
+}
+
+// LimitSuccessful sets the number of times a job is allowed to run
+// successfully before it is completed. Failed runs are not counted.
+// If 0 or a negative value is provided, the limit will be set to 0 (no limit).
+func (t *Trigger) LimitSuccessful(n int64) *Trigger {
+	t.Limit(n)
+	t.successful = true
 	return t
 }
 
 // Next returns the next scheduled time for the Trigger, counting from the
-// current time. If the interval is 0, a zeroed time.Time will be returned.
+// current time. If there is no such time, a zeroed time.Time will be
+// returned.
 func (t *Trigger) Next() time.Time {
+	return t.NextAfter(time.Now())
+}
+
+// NextAfter returns the first scheduled time for the Trigger after tm.
+// If there is no such time, a zeroed time.Time will be returned.
+// If the Trigger has an end time, no time after it is returned. The end time
+// is compared against the scheduled time before any jitter, splay or random
+// time within a window.
+func (t *Trigger) NextAfter(tm time.Time) time.Time {
+	from := tm.Add(-t.jitter - t.splay - t.spread())
+	for i := 0; i < maxCompositeRuns; i++ {
+		next := t.include(t.next(from))
+		if next.IsZero() || (!t.until.IsZero() && next.After(t.until)) {
+			return time.Time{}
+		}
+		if delayed := next.Add(t.delay(next)); delayed.After(tm) {
+			return delayed
+		}
+		from = next
+	}
+	return time.Time{}
+}
+
+// Exclude attaches a Calendar to the Trigger. Scheduled times excluded by the
+// calendar are skipped.
+func (t *Trigger) Exclude(cal Calendar) *Trigger {
+	t.excluded = cal
+	t.shift = false
+	return t
+}
+
+// From sets the start time from which the recurrence is counted from.
+func (t *Trigger) From(tm time.Time) *Trigger {
+	t.start = tm
+	return t
+}
+
+// Seed sets the seed used to choose the jitter and random times of the
+// Trigger, making the scheduled times reproducible. See Jitter,
+// RandomlyEvery and RandomlyBetween.
+func (t *Trigger) Seed(n int64) *Trigger {
+	t.seed = n
+	return t
+}
+
+// Shift attaches a Calendar to the Trigger. Scheduled times excluded by the
+// calendar are moved to the same time of day on the next day that is not
+// excluded, e.g. the next business day.
+func (t *Trigger) Shift(cal Calendar) *Trigger {
+	t.excluded = cal
+	t.shift = true
+	return t
+}
+
+// Splay delays every scheduled time of the Trigger by a fixed duration
+// between 0 and window, derived from a hash of key. Using a different key
+// for every job or instance spreads their runs across the window, while
+// keeping them the same between restarts.
+// If 0 or a negative window is provided, the splay is removed.
+func (t *Trigger) Splay(key string, window time.Duration) *Trigger {
+	t.splay = 0
+	if window > 0 {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		t.splay = time.Duration(h.Sum64() % uint64(window))
+	}
+	return t
+}
+
+// Until sets the end time of the Trigger. No run is scheduled after it.
+// A zeroed time.Time removes the end time.
+func (t *Trigger) Until(tm time.Time) *Trigger {
+	t.until = tm
+	return t
+}
+
+// next returns the first time after tm produced by the recurrence of the
+// Trigger, ignoring the end time.
+func (t *Trigger) next(tm time.Time) time.Time {
+	if t.rule != nil {
+		return t.rule.next(t.start, tm)
+	}
 	if t.interval == 0 {
 		return time.Time{}
 	}
-	now := time.Now()
 	next := t.start.Add(t.interval)
-	current := int64(0)
-	for next.Before(now) {
-		current = current + 1
-		if t.limit > 0 && current >= t.limit {
+	if next.After(tm) {
+		return next
+	}
+	n := tm.Sub(t.start) / t.interval
+	return t.start.Add((n + 1) * t.interval)
+}
+
+// delay returns the jitter, splay and random time within the window for
+// the scheduled time tm.
+func (t *Trigger) delay(tm time.Time) time.Duration {
+	d := t.splay
+	z := splitmix(uint64(t.seed) ^ uint64(tm.UnixNano()))
+	if t.jitter > 0 {
+		d += time.Duration(z % uint64(t.jitter))
+	}
+	if spread := t.spread(); spread > 0 {
+		d += time.Duration(splitmix(z) % uint64(spread))
+	}
+	return d
+}
+
+// splitmix returns the next value of a splitmix64 generator in state z.
+func splitmix(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// include returns the first time from next not excluded by the calendar of
+// the Trigger, either by skipping or shifting excluded times.
+func (t *Trigger) include(next time.Time) time.Time {
+	if t.excluded == nil {
+		return next
+	}
+	for i := 0; i < maxExcludedRuns && !next.IsZero(); i++ {
+		if !t.excluded.Excludes(next) {
 			return next
 		}
-		next = next.Add(t.interval)
+		if t.shift {
+			next = next.AddDate(0, 0, 1)
+		} else {
+			next = t.next(next)
+		}
 	}
-	return next
+	return time.Time{}
 }
 
-// From sets the start time from which the recurrence is counted from.
-func (t *Trigger) From(tm time.Time) *Trigger {
-	t.start = tm
-	return t
+// nextRun returns the next scheduled time for a job whose last run started
+// and finished at the given times.
+func (t *Trigger) nextRun(started, finished time.Time) time.Time {
+	if !t.delayed || t.interval == 0 || finished.IsZero() {
+		return t.NextAfter(started)
+	}
+	c := *t
+	c.start = finished
+	return c.NextAfter(finished)
+}
+
+// scheduled returns whether a recurrence has been set on the Trigger.
+func (t *Trigger) scheduled() bool {
+	return t.interval > 0 || t.rule != nil
 }
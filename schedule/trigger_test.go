@@ -12,6 +12,47 @@ func TestNewTrigger(t *testing.T) {
 	}
 }
 
+func TestTrigger_After(t *testing.T) {
+	start := time.Now()
+	trigger := NewTrigger().From(start).After(time.Hour)
+	if n := trigger.NextAfter(start); !n.Equal(start.Add(time.Hour)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, start.Add(time.Hour))
+	}
+}
+
+func TestTrigger_At(t *testing.T) {
+	tm := time.Now().Add(time.Hour)
+	trigger := NewTrigger().At(tm)
+	if n := trigger.Next(); !n.Equal(tm) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, tm)
+	}
+	if n := trigger.NextAfter(tm); !n.IsZero() {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, time.Time{})
+	}
+}
+
+func TestTrigger_AtTimes(t *testing.T) {
+	now := time.Now()
+	trigger := NewTrigger().AtTimes(now.Add(2*time.Hour), now.Add(time.Hour))
+	if n := trigger.Next(); !n.Equal(now.Add(time.Hour)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, now.Add(time.Hour))
+	}
+	if n := trigger.NextAfter(now.Add(time.Hour)); !n.Equal(now.Add(2 * time.Hour)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, now.Add(2*time.Hour))
+	}
+}
+
+func TestTrigger_Between(t *testing.T) {
+	start := time.Now()
+	trigger := NewTrigger().Every("10m").Between(start, start.Add(25*time.Minute))
+	if n := trigger.NextAfter(start); !n.Equal(start.Add(10 * time.Minute)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, start.Add(10*time.Minute))
+	}
+	if n := trigger.NextAfter(start.Add(20 * time.Minute)); !n.IsZero() {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, time.Time{})
+	}
+}
+
 func TestTrigger_Every(t *testing.T) {
 	trigger := NewTrigger()
 	trigger.Every("15m")
@@ -24,6 +65,23 @@ func TestTrigger_Every(t *testing.T) {
 	}
 }
 
+func TestTrigger_EveryAfterCompletion(t *testing.T) {
+	start := time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)
+	trigger := NewTrigger().From(start).EveryAfterCompletion("30s")
+	if n := trigger.nextRun(time.Time{}, time.Time{}); !n.Equal(start.Add(30 * time.Second)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, start.Add(30*time.Second))
+	}
+	started := start.Add(30 * time.Second)
+	finished := started.Add(40 * time.Second)
+	if n := trigger.nextRun(started, finished); !n.Equal(finished.Add(30 * time.Second)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, finished.Add(30*time.Second))
+	}
+	trigger.Every("30s")
+	if n := trigger.nextRun(started, finished); !n.Equal(start.Add(60 * time.Second)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, start.Add(60*time.Second))
+	}
+}
+
 func TestTrigger_EveryNegative(t *testing.T) {
 	trigger := NewTrigger()
 	trigger.Every("-15m")
@@ -46,6 +104,51 @@ func TestTrigger_EveryPanic(t *testing.T) {
 	trigger.Every("15x")
 }
 
+func TestTrigger_Exclude(t *testing.T) {
+	// 2028-01-01 is a Saturday.
+	start := time.Date(2027, 12, 31, 9, 0, 0, 0, time.UTC)
+	weekends := NewHolidayCalendar().ExcludeWeekday(time.Saturday, time.Sunday)
+	trigger := NewTrigger().From(start).Every("24h").Exclude(weekends)
+	next := time.Date(2028, 1, 3, 9, 0, 0, 0, time.UTC)
+	if n := trigger.NextAfter(start); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+}
+
+func TestTrigger_ExcludeAll(t *testing.T) {
+	all := NewHolidayCalendar().ExcludeRange(time.Time{}, time.Now().AddDate(100, 0, 0))
+	trigger := NewTrigger().Every("1h").Exclude(all)
+	if n := trigger.Next(); !n.IsZero() {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, time.Time{})
+	}
+}
+
+func TestTrigger_Jitter(t *testing.T) {
+	start := time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)
+	trigger := NewTrigger().From(start).Every("1h").Jitter(10 * time.Minute).Seed(42)
+	after := start
+	delays := make(map[time.Duration]bool)
+	for i := 1; i <= 10; i++ {
+		n := trigger.NextAfter(after)
+		slot := start.Add(time.Duration(i) * time.Hour)
+		if n.Before(slot) || !n.Before(slot.Add(10*time.Minute)) {
+			t.Fatalf("Next time %v was not within jitter of %v", n, slot)
+		}
+		if again := trigger.NextAfter(after); !again.Equal(n) {
+			t.Errorf("Next time was not stable. Got %v, then %v", n, again)
+		}
+		delays[n.Sub(slot)] = true
+		after = n
+	}
+	if len(delays) < 2 {
+		t.Error("Jitter did not vary between scheduled times")
+	}
+	other := NewTrigger().From(start).Every("1h").Jitter(10 * time.Minute).Seed(42)
+	if a, b := trigger.NextAfter(start), other.NextAfter(start); !a.Equal(b) {
+		t.Errorf("Jitter with the same seed did not match. Got %v and %v", a, b)
+	}
+}
+
 func TestTrigger_Limit(t *testing.T) {
 	trigger := NewTrigger()
 	trigger.Limit(10)
@@ -54,6 +157,14 @@ func TestTrigger_Limit(t *testing.T) {
 	}
 }
 
+func TestTrigger_LimitSuccessful(t *testing.T) {
+	trigger := NewTrigger()
+	trigger.LimitSuccessful(10)
+	if trigger.limit != 10 || !trigger.successful {
+		t.Errorf("Limit did not match. Got %v, expected 10 successful runs", trigger.limit)
+	}
+}
+
 func TestTrigger_LimitNegative(t *testing.T) {
 	trigger := NewTrigger()
 	trigger.Limit(-10)
@@ -92,7 +203,7 @@ func TestTrigger_NextBefore(t *testing.T) {
 func TestTrigger_NextLimit(t *testing.T) {
 	now := time.Now()
 	start := now.AddDate(0, -1, 0)
-	next := start.Add(30 * time.Minute)
+	next := now.Add(30 * time.Minute)
 	trigger := &Trigger{
 		interval: 30 * time.Minute,
 		start:    start,
@@ -104,6 +215,27 @@ func TestTrigger_NextLimit(t *testing.T) {
 	}
 }
 
+func TestTrigger_NextAfter(t *testing.T) {
+	start := time.Now()
+	trigger := &Trigger{
+		interval: 30 * time.Minute,
+		start:    start,
+	}
+	tests := []struct {
+		after time.Time
+		next  time.Time
+	}{
+		{time.Time{}, start.Add(30 * time.Minute)},
+		{start.Add(30 * time.Minute), start.Add(60 * time.Minute)},
+		{start.Add(100 * time.Minute), start.Add(120 * time.Minute)},
+	}
+	for _, test := range tests {
+		if n := trigger.NextAfter(test.after); !n.Equal(test.next) {
+			t.Errorf("Next time did not match. Got %v, expected %v.", n, test.next)
+		}
+	}
+}
+
 func TestTrigger_NextZero(t *testing.T) {
 	trigger := &Trigger{
 		interval: 0,
@@ -128,3 +260,55 @@ func TestTrigger_From(t *testing.T) {
 		)
 	}
 }
+
+func TestTrigger_Shift(t *testing.T) {
+	// 2028-01-01 is a Saturday.
+	start := time.Date(2028, 1, 1, 9, 0, 0, 0, time.UTC)
+	holidays := NewHolidayCalendar().
+		ExcludeWeekday(time.Saturday, time.Sunday).
+		ExcludeDate(time.Date(2028, 1, 3, 0, 0, 0, 0, time.UTC))
+	trigger := NewTrigger().From(start).At(start).Shift(holidays)
+	next := time.Date(2028, 1, 4, 9, 0, 0, 0, time.UTC)
+	if n := trigger.NextAfter(start.Add(-time.Hour)); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+}
+
+func TestTrigger_Splay(t *testing.T) {
+	start := time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewTrigger().From(start).Every("1h").Splay("instance-a", 30*time.Minute)
+	b := NewTrigger().From(start).Every("1h").Splay("instance-b", 30*time.Minute)
+	if a.splay == b.splay {
+		t.Errorf("Splay did not differ between keys. Got %v for both", a.splay)
+	}
+	if a.splay < 0 || a.splay >= 30*time.Minute {
+		t.Errorf("Splay %v was not within window", a.splay)
+	}
+	next := start.Add(time.Hour + a.splay)
+	if n := a.NextAfter(start); !n.Equal(next) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next)
+	}
+	if n := a.NextAfter(next); !n.Equal(next.Add(time.Hour)) {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, next.Add(time.Hour))
+	}
+	again := NewTrigger().Splay("instance-a", 30*time.Minute)
+	if again.splay != a.splay {
+		t.Errorf("Splay was not stable. Got %v, expected %v", again.splay, a.splay)
+	}
+}
+
+func TestTrigger_Until(t *testing.T) {
+	trigger := NewTrigger()
+	tm := time.Now().Add(15 * time.Minute)
+	trigger.Every("10m").Until(tm)
+	if !trigger.until.Equal(tm) {
+		t.Errorf(
+			"End time does not match. Got %v, expected %v",
+			trigger.until,
+			tm,
+		)
+	}
+	if n := trigger.NextAfter(tm.Add(-5 * time.Minute)); !n.IsZero() {
+		t.Errorf("Next time did not match. Got %v, expected %v", n, time.Time{})
+	}
+}
@@ -0,0 +1,260 @@
+package schedule
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// errWatchUnsupported is returned by watchNative when native file system
+// notifications are not available on the platform.
+var errWatchUnsupported = errors.New("schedule: file system notifications are not supported")
+
+// A Watcher represents a watch on a set of files and directories, emitting
+// the paths of changed files on the Changes channel.
+// Directories are watched for files being created, written, moved or
+// removed directly inside them, but not in their subdirectories.
+// On Linux, changes are detected through inotify. On other platforms, or if
+// inotify is not available, the paths are polled for changes.
+type Watcher struct {
+	paths    []string
+	patterns []string
+	debounce time.Duration
+	interval time.Duration
+	polling  bool
+	changes  chan []string
+	done     chan struct{}
+	mutex    sync.Mutex
+	started  bool
+	stop     func()
+}
+
+// NewWatcher creates a new Watcher for the given files and directories.
+// By default, changes are debounced for 100 milliseconds and paths are polled
+// every second if native notifications are not available. See Debounce and
+// Poll.
+func NewWatcher(paths ...string) *Watcher {
+	return &Watcher{
+		paths:    paths,
+		debounce: 100 * time.Millisecond,
+		interval: time.Second,
+		changes:  make(chan []string, 10),
+		done:     make(chan struct{}),
+	}
+}
+
+// Changes returns the channel on which the paths of changed files are
+// emitted, sorted and without duplicates. The channel is closed when the
+// Watcher is closed.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Close stops watching for changes.
+func (w *Watcher) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	default:
+	}
+	close(w.done)
+	if w.stop != nil {
+		w.stop()
+	}
+	if !w.started {
+		close(w.changes)
+	}
+	return nil
+}
+
+// Debounce sets the debounce window of the Watcher. Changes are collected
+// until no change has been detected for d, and then emitted together.
+// If 0 or a negative value is provided, every change is emitted separately.
+func (w *Watcher) Debounce(d time.Duration) *Watcher {
+	if d < 0 {
+		d = 0
+	}
+	w.debounce = d
+	return w
+}
+
+// Match restricts the Watcher to changed files matching any of the given
+// glob patterns, as accepted by filepath.Match. Patterns containing a path
+// separator are matched against the full path, while other patterns are
+// matched against the file name, e.g. Watcher.Match("*.csv").
+func (w *Watcher) Match(patterns ...string) *Watcher {
+	w.patterns = append(w.patterns, patterns...)
+	return w
+}
+
+// Poll makes the Watcher poll the paths for changes every d, instead of
+// using native notifications.
+// If 0 or a negative value is provided, native notifications are used when
+// available.
+func (w *Watcher) Poll(d time.Duration) *Watcher {
+	w.polling = d > 0
+	if d > 0 {
+		w.interval = d
+	}
+	return w
+}
+
+// Start begins watching for changes.
+// If the Watcher has been started or closed, an error is returned.
+func (w *Watcher) Start() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	select {
+	case <-w.done:
+		return errors.New("schedule: watcher is closed")
+	default:
+	}
+	if w.started {
+		return errors.New("schedule: watcher is already running")
+	}
+	events := make(chan string)
+	err := errWatchUnsupported
+	if !w.polling {
+		w.stop, err = watchNative(w.paths, events, w.done)
+	}
+	if err == errWatchUnsupported {
+		go w.poll(events, w.snapshot())
+	} else if err != nil {
+		return err
+	}
+	w.started = true
+	go w.collect(events)
+	return nil
+}
+
+// collect receives changed paths from events, emitting the matching ones to
+// the changes channel once the debounce window has passed.
+func (w *Watcher) collect(events <-chan string) {
+	defer close(w.changes)
+	changed := make(map[string]bool)
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-w.done:
+			return
+		case path := <-events:
+			if !w.matches(path) {
+				continue
+			}
+			changed[path] = true
+			if w.debounce > 0 {
+				timer = time.After(w.debounce)
+				continue
+			}
+		case <-timer:
+		}
+		paths := make([]string, 0, len(changed))
+		for path := range changed {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		changed = make(map[string]bool)
+		timer = nil
+		select {
+		case w.changes <- paths:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// matches returns whether path matches the patterns of the Watcher.
+func (w *Watcher) matches(path string) bool {
+	if len(w.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range w.patterns {
+		name := filepath.Base(path)
+		if filepath.Base(pattern) != pattern {
+			name = path
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// poll polls the paths of the Watcher for changes since the snapshot files,
+// sending the paths of created, modified and removed files to events.
+func (w *Watcher) poll(events chan<- string, files map[string]os.FileInfo) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+		current := w.snapshot()
+		var changed []string
+		for path, info := range current {
+			if prev, ok := files[path]; !ok || !prev.ModTime().Equal(info.ModTime()) || prev.Size() != info.Size() {
+				changed = append(changed, path)
+			}
+		}
+		for path := range files {
+			if _, ok := current[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+		files = current
+		for _, path := range changed {
+			select {
+			case events <- path:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// snapshot returns the file information of the watched files and the files
+// directly inside the watched directories.
+func (w *Watcher) snapshot() map[string]os.FileInfo {
+	files := make(map[string]os.FileInfo)
+	for _, path := range w.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			files[path] = info
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files[filepath.Join(path, entry.Name())] = entry
+			}
+		}
+	}
+	return files
+}
+
+// Watch requests a run of the job every time the Watcher emits changes,
+// until the Watcher is closed. The job function is called with its own
+// arguments followed by the changed paths as a []string, e.g.
+// func(paths []string) for a job without arguments. If the job falls behind,
+// the oldest pending changes are dropped. See TriggerWith.
+func (j *Job) Watch(w *Watcher) {
+	go func() {
+		for paths := range w.Changes() {
+			j.TriggerWith(append(j.Args(), paths)...)
+		}
+	}()
+}
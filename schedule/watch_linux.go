@@ -0,0 +1,67 @@
+//go:build linux
+
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask is the set of inotify events watched for.
+const inotifyMask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY |
+	syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE
+
+// watchNative watches paths using inotify, sending the paths of changed
+// files to events until done is closed. The returned function releases the
+// inotify instance.
+func watchNative(paths []string, events chan<- string, done <-chan struct{}) (func(), error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, errWatchUnsupported
+	}
+	watches := make(map[int32]string)
+	for _, path := range paths {
+		wd, err := syscall.InotifyAddWatch(fd, path, inotifyMask)
+		if err != nil {
+			syscall.Close(fd)
+			return nil, fmt.Errorf("schedule: could not watch %s: %v", path, err)
+		}
+		watches[int32(wd)] = path
+	}
+	// A non-blocking descriptor is registered with the runtime poller, so
+	// closing the file interrupts a pending read.
+	file := os.NewFile(uintptr(fd), "inotify")
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				start := offset + syscall.SizeofInotifyEvent
+				offset = start + int(event.Len)
+				path, ok := watches[event.Wd]
+				if !ok || event.Mask&inotifyMask == 0 || event.Mask&syscall.IN_ISDIR != 0 {
+					continue
+				}
+				if event.Len > 0 {
+					path = filepath.Join(path, strings.TrimRight(string(buf[start:offset]), "\x00"))
+				}
+				select {
+				case events <- path:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		file.Close()
+	}, nil
+}
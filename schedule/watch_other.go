@@ -0,0 +1,9 @@
+//go:build !linux
+
+package schedule
+
+// watchNative reports that native file system notifications are not
+// available, making the Watcher poll for changes instead.
+func watchNative(paths []string, events chan<- string, done <-chan struct{}) (func(), error) {
+	return nil, errWatchUnsupported
+}
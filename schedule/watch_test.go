@@ -0,0 +1,115 @@
+package schedule
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// receive returns the next changes emitted by the Watcher, failing the test
+// if none are emitted within a second.
+func receive(t *testing.T, w *Watcher) []string {
+	select {
+	case paths := <-w.Changes():
+		return paths
+	case <-time.After(time.Second):
+		t.Fatal("Watcher did not emit any changes")
+	}
+	return nil
+}
+
+func testWatcher(t *testing.T, w *Watcher, dir string) {
+	if err := w.Start(); err != nil {
+		t.Fatalf("Watcher errored on Start: %v", err)
+	}
+	defer w.Close()
+	for _, name := range []string{"a.csv", "b.txt", "c.csv"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("test"), 0644); err != nil {
+			t.Fatalf("Could not write test file: %v", err)
+		}
+	}
+	expected := []string{filepath.Join(dir, "a.csv"), filepath.Join(dir, "c.csv")}
+	if paths := receive(t, w); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Changed paths did not match. Got %v, expected %v", paths, expected)
+	}
+	if err := os.Remove(filepath.Join(dir, "a.csv")); err != nil {
+		t.Fatalf("Could not remove test file: %v", err)
+	}
+	expected = []string{filepath.Join(dir, "a.csv")}
+	if paths := receive(t, w); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Changed paths did not match. Got %v, expected %v", paths, expected)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schedule")
+	if err != nil {
+		t.Fatalf("Could not create test directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	testWatcher(t, NewWatcher(dir).Match("*.csv").Debounce(50*time.Millisecond), dir)
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schedule")
+	if err != nil {
+		t.Fatalf("Could not create test directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	testWatcher(t, NewWatcher(dir).Match("*.csv").Poll(10*time.Millisecond).Debounce(50*time.Millisecond), dir)
+}
+
+func TestWatcher_Close(t *testing.T) {
+	w := NewWatcher(os.TempDir())
+	if err := w.Start(); err != nil {
+		t.Fatalf("Watcher errored on Start: %v", err)
+	}
+	if err := w.Start(); err == nil {
+		t.Error("Watcher did not error on Start while already running")
+	}
+	w.Close()
+	if _, ok := <-w.Changes(); ok {
+		t.Error("Changes channel was not closed")
+	}
+	if err := w.Start(); err == nil {
+		t.Error("Watcher did not error on Start after Close")
+	}
+}
+
+func TestJob_Watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schedule")
+	if err != nil {
+		t.Fatalf("Could not create test directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	q := NewQueue()
+	j, err := NewJob("ingest", func(prefix string, paths []string) string {
+		return prefix + filepath.Base(paths[0])
+	}, "ingest ")
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	q.Add(j)
+	w := NewWatcher(dir).Debounce(20 * time.Millisecond)
+	if err := w.Start(); err != nil {
+		t.Fatalf("Watcher errored on Start: %v", err)
+	}
+	defer w.Close()
+	j.Watch(w)
+	if err := ioutil.WriteFile(filepath.Join(dir, "data.csv"), []byte("test"), 0644); err != nil {
+		t.Fatalf("Could not write test file: %v", err)
+	}
+	for i := 0; i < 100 && len(q.results) == 0; i++ {
+		q.Run()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(q.results) != 1 {
+		t.Fatalf("Number of results in buffer did not match. Got %d, expected 1.", len(q.results))
+	}
+	if res := <-q.results; res.Results[0] != "ingest data.csv" {
+		t.Errorf("Job result did not match. Got %v, expected %v", res.Results[0], "ingest data.csv")
+	}
+}
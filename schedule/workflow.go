@@ -0,0 +1,291 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrWorkflowCycle is returned when the dependencies of a Workflow contain a
+// cycle.
+var ErrWorkflowCycle = errors.New("schedule: workflow dependency cycle")
+
+// A FailurePolicy represents how a Workflow proceeds when one of its jobs
+// fails.
+type FailurePolicy int
+
+const (
+	// FailFast stops the workflow when the job fails. Jobs that have not
+	// started are skipped, while running jobs are allowed to finish.
+	FailFast FailurePolicy = iota
+	// Continue runs the jobs depending on the job as if it succeeded.
+	Continue
+	// SkipDownstream skips every job depending, directly or indirectly, on
+	// the job, while the rest of the workflow continues.
+	SkipDownstream
+)
+
+// String returns the name of the failure policy.
+func (p FailurePolicy) String() string {
+	switch p {
+	case FailFast:
+		return "fail-fast"
+	case Continue:
+		return "continue"
+	case SkipDownstream:
+		return "skip-downstream"
+	}
+	return fmt.Sprintf("FailurePolicy(%d)", int(p))
+}
+
+// A WorkflowError represents the failure of one or more jobs in a Workflow.
+// The errors of the failed jobs are stored by job name in .Failed and the
+// names of the jobs skipped as a result in .Skipped.
+type WorkflowError struct {
+	Name    string
+	Failed  map[string]error
+	Skipped []string
+}
+
+// Error returns the error message.
+func (e *WorkflowError) Error() string {
+	names := make([]string, 0, len(e.Failed))
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	failures := make([]string, len(names))
+	for i, name := range names {
+		failures[i] = fmt.Sprintf("%s: %v", name, e.Failed[name])
+	}
+	msg := fmt.Sprintf("schedule: workflow %s failed: %s", e.Name, strings.Join(failures, "; "))
+	if len(e.Skipped) > 0 {
+		msg += " (skipped " + strings.Join(e.Skipped, ", ") + ")"
+	}
+	return msg
+}
+
+// workflowNode represents a job in a Workflow.
+type workflowNode struct {
+	job    *Job
+	after  []string
+	policy FailurePolicy
+}
+
+// nodeStatus represents the progress of a job during a workflow run.
+type nodeStatus int
+
+const (
+	nodePending nodeStatus = iota
+	nodeRunning
+	nodeSucceeded
+	nodeFailed
+	nodeSkipped
+)
+
+// A Workflow represents a set of jobs run as a directed acyclic graph, where
+// every job runs once all the jobs it depends on have finished. Jobs without
+// pending dependencies run in parallel.
+// Jobs are identified by their names, which need to be unique within the
+// workflow.
+type Workflow struct {
+	Name  string
+	nodes []*workflowNode
+	mutex sync.RWMutex
+}
+
+// NewWorkflow creates a new, empty Workflow.
+func NewWorkflow(name string) *Workflow {
+	return &Workflow{
+		Name: name,
+	}
+}
+
+// Add adds a job to the workflow, running after the jobs with the given
+// names have finished, e.g. Workflow.Add(aggregate, "extract-a",
+// "extract-b"). The dependencies may be added to the workflow later.
+// By default, the failure policy of the job is FailFast. See Policy.
+// A job added to a workflow fails when its function returns a non-nil error
+// as its last return value. See Job.FailOnError.
+func (w *Workflow) Add(job *Job, after ...string) *Workflow {
+	job.FailOnError()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.nodes = append(w.nodes, &workflowNode{job: job, after: after})
+	return w
+}
+
+// Policy sets the failure policy of the job with the given name.
+// If the workflow has no such job, the call has no effect.
+func (w *Workflow) Policy(name string, policy FailurePolicy) *Workflow {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, n := range w.nodes {
+		if n.job.Name == name {
+			n.policy = policy
+		}
+	}
+	return w
+}
+
+// Validate checks that the job names of the workflow are unique, that every
+// dependency is part of the workflow and that the dependencies contain no
+// cycles. A cycle is reported as an error wrapping ErrWorkflowCycle.
+func (w *Workflow) Validate() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	nodes := make(map[string]*workflowNode, len(w.nodes))
+	for _, n := range w.nodes {
+		if _, ok := nodes[n.job.Name]; ok {
+			return fmt.Errorf("schedule: workflow %s has more than one job named %s", w.Name, n.job.Name)
+		}
+		nodes[n.job.Name] = n
+	}
+	for _, n := range w.nodes {
+		for _, name := range n.after {
+			if _, ok := nodes[name]; !ok {
+				return fmt.Errorf("schedule: job %s in workflow %s depends on unknown job %s", n.job.Name, w.Name, name)
+			}
+		}
+	}
+	// Depth-first search, where a job found on the current path closes a
+	// cycle. Jobs on the current path are marked as running, and jobs
+	// already searched as succeeded.
+	visited := make(map[string]nodeStatus, len(w.nodes))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch visited[name] {
+		case nodeRunning:
+			for i, p := range path {
+				if p == name {
+					cycle := append(append([]string(nil), path[i:]...), name)
+					return fmt.Errorf("%w in %s: %s", ErrWorkflowCycle, w.Name, strings.Join(cycle, " -> "))
+				}
+			}
+		case nodeSucceeded:
+			return nil
+		}
+		visited[name] = nodeRunning
+		path = append(path, name)
+		for _, dep := range nodes[name].after {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		visited[name] = nodeSucceeded
+		return nil
+	}
+	for _, n := range w.nodes {
+		if err := visit(n.job.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Job validates the workflow and creates a Job running it, so that it may
+// be scheduled and added to a Queue like any other job, e.g.
+//
+//	job, err := workflow.Job()
+//	job.Schedule().Cron("0 2 * * *")
+//	scheduler.Add(job)
+//
+// The job is named after the workflow, and fails with the error returned by
+// Run.
+func (w *Workflow) Job() (*Job, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	job, err := NewJob(w.Name, w.Run)
+	if err != nil {
+		return nil, err
+	}
+	return job.FailOnError(), nil
+}
+
+// Run validates and runs the workflow, returning once every job has either
+// finished or been skipped. Every job is run through Job.Run, so failed runs
+// are recorded in the history of the job.
+// If any job fails, a *WorkflowError is returned.
+func (w *Workflow) Run() error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	w.mutex.RLock()
+	nodes := append([]*workflowNode(nil), w.nodes...)
+	policies := make(map[string]FailurePolicy, len(w.nodes))
+	for _, n := range w.nodes {
+		policies[n.job.Name] = n.policy
+	}
+	w.mutex.RUnlock()
+	type outcome struct {
+		name string
+		err  error
+	}
+	status := make(map[string]nodeStatus, len(nodes))
+	done := make(chan outcome)
+	running := 0
+	aborted := false
+	errs := make(map[string]error)
+	for {
+		for started := true; started; {
+			started = false
+			for _, n := range nodes {
+				name := n.job.Name
+				if status[name] != nodePending {
+					continue
+				}
+				ready, skip := true, aborted
+				for _, dep := range n.after {
+					switch status[dep] {
+					case nodePending, nodeRunning:
+						ready = false
+					case nodeSkipped:
+						skip = true
+					case nodeFailed:
+						skip = skip || policies[dep] != Continue
+					}
+				}
+				if !ready && !aborted {
+					continue
+				}
+				started = true
+				if skip {
+					status[name] = nodeSkipped
+					continue
+				}
+				status[name] = nodeRunning
+				running++
+				go func(job *Job) {
+					_, err := job.Run()
+					done <- outcome{job.Name, err}
+				}(n.job)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		o := <-done
+		running--
+		status[o.name] = nodeSucceeded
+		if o.err != nil {
+			status[o.name] = nodeFailed
+			errs[o.name] = o.err
+			aborted = aborted || policies[o.name] == FailFast
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	err := &WorkflowError{Name: w.Name, Failed: errs}
+	for _, n := range nodes {
+		if status[n.job.Name] == nodeSkipped {
+			err.Skipped = append(err.Skipped, n.job.Name)
+		}
+	}
+	return err
+}
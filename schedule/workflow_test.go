@@ -0,0 +1,181 @@
+package schedule
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records the order in which workflow jobs run.
+type recorder struct {
+	mutex sync.Mutex
+	order []string
+}
+
+func (r *recorder) job(t *testing.T, name string, fail bool) *Job {
+	j, err := NewJob(name, func() error {
+		time.Sleep(10 * time.Millisecond)
+		r.mutex.Lock()
+		r.order = append(r.order, name)
+		r.mutex.Unlock()
+		if fail {
+			return errors.New(name + " failed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Could not create test Job: %v", err)
+	}
+	return j
+}
+
+func (r *recorder) ran(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, n := range r.order {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWorkflow_Run(t *testing.T) {
+	r := &recorder{}
+	started, release := make(chan string, 2), make(chan struct{})
+	extract := func(name string) *Job {
+		j, err := NewJob(name, func() {
+			started <- name
+			<-release
+			r.mutex.Lock()
+			r.order = append(r.order, name)
+			r.mutex.Unlock()
+		})
+		if err != nil {
+			t.Fatalf("Could not create test Job: %v", err)
+		}
+		return j
+	}
+	w := NewWorkflow("nightly").
+		Add(r.job(t, "aggregate", false), "extract-a", "extract-b").
+		Add(extract("extract-a")).
+		Add(extract("extract-b")).
+		Add(r.job(t, "report", false), "aggregate")
+	done := make(chan error)
+	go func() {
+		done <- w.Run()
+	}()
+	// Both extract jobs need to be running at the same time to get past
+	// this point.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("Independent jobs did not run in parallel")
+		}
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Workflow errored on Run: %v", err)
+	}
+	if len(r.order) != 4 || r.order[2] != "aggregate" || r.order[3] != "report" {
+		t.Errorf("Workflow order did not match. Got %v", r.order)
+	}
+}
+
+func TestWorkflow_Policies(t *testing.T) {
+	tests := []struct {
+		policy  FailurePolicy
+		ran     []string
+		skipped []string
+	}{
+		{FailFast, []string{"a"}, []string{"b", "c"}},
+		{Continue, []string{"a", "b", "c", "d"}, nil},
+		{SkipDownstream, []string{"a", "d"}, []string{"b", "c"}},
+	}
+	for _, test := range tests {
+		r := &recorder{}
+		w := NewWorkflow("test").
+			Add(r.job(t, "a", true)).
+			Add(r.job(t, "b", false), "a").
+			Add(r.job(t, "c", false), "b").
+			Add(r.job(t, "d", false), "e").
+			Add(r.job(t, "e", false)).
+			Policy("a", test.policy)
+		err := w.Run()
+		var werr *WorkflowError
+		if !errors.As(err, &werr) {
+			t.Fatalf("Workflow error was not a *WorkflowError. Got %v", err)
+		}
+		if werr.Failed["a"] == nil || len(werr.Failed) != 1 {
+			t.Errorf("Failed jobs for %v did not match. Got %v", test.policy, werr.Failed)
+		}
+		for _, name := range test.ran {
+			if !r.ran(name) {
+				t.Errorf("Job %s did not run for %v", name, test.policy)
+			}
+		}
+		skipped := werr.Skipped
+		if test.policy == FailFast && len(skipped) > 2 {
+			// Depending on timing, d may already have started when a failed.
+			skipped = skipped[:2]
+		}
+		if !reflect.DeepEqual(skipped, test.skipped) {
+			t.Errorf("Skipped jobs for %v did not match. Got %v, expected %v", test.policy, werr.Skipped, test.skipped)
+		}
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	r := &recorder{}
+	w := NewWorkflow("test").
+		Add(r.job(t, "a", false), "c").
+		Add(r.job(t, "b", false), "a").
+		Add(r.job(t, "c", false), "b")
+	err := w.Validate()
+	if !errors.Is(err, ErrWorkflowCycle) {
+		t.Fatalf("Workflow error did not match. Got %v, expected %v", err, ErrWorkflowCycle)
+	}
+	if expected := "schedule: workflow dependency cycle in test: a -> c -> b -> a"; err.Error() != expected {
+		t.Errorf("Workflow error did not match. Got %q, expected %q", err, expected)
+	}
+	if _, err := w.Job(); err == nil {
+		t.Error("Workflow did not error on Job with a cycle")
+	}
+	w = NewWorkflow("test").Add(r.job(t, "a", false), "missing")
+	if err := w.Validate(); err == nil {
+		t.Error("Workflow did not error on an unknown dependency")
+	}
+	w = NewWorkflow("test").Add(r.job(t, "a", false)).Add(r.job(t, "a", false))
+	if err := w.Validate(); err == nil {
+		t.Error("Workflow did not error on duplicate jobs")
+	}
+}
+
+func TestWorkflow_Job(t *testing.T) {
+	r := &recorder{}
+	w := NewWorkflow("nightly").
+		Add(r.job(t, "extract", false)).
+		Add(r.job(t, "load", true), "extract")
+	j, err := w.Job()
+	if err != nil {
+		t.Fatalf("Workflow errored on Job: %v", err)
+	}
+	j.Schedule().Every("1ms").Limit(1)
+	q := NewQueue()
+	q.Add(j)
+	time.Sleep(time.Millisecond)
+	q.Run()
+	time.Sleep(50 * time.Millisecond)
+	if len(q.errors) != 1 {
+		t.Fatalf("Number of errors in buffer did not match. Got %d, expected 1.", len(q.errors))
+	}
+	if e := <-q.errors; e.Name != "nightly" {
+		t.Errorf("Error job name did not match. Got %s, expected nightly", e.Name)
+	}
+	if !reflect.DeepEqual(r.order, []string{"extract", "load"}) {
+		t.Errorf("Workflow order did not match. Got %v", r.order)
+	}
+}